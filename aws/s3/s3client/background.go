@@ -0,0 +1,402 @@
+package s3client
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// backgroundClient implements S3Client methods without a context by calling
+// their context-aware counterparts with context.Background().
+type backgroundClient struct {
+	S3ClientCtx
+}
+
+// CreateBucket ...
+func (c backgroundClient) CreateBucket(bucket string) (string, error) {
+	return c.CreateBucketWithContext(context.Background(), bucket)
+}
+
+// CreateBucketWithOptions creates a bucket with the options provided.
+func (c backgroundClient) CreateBucketWithOptions(bucket string, opts CreateBucketOptions) (string, error) {
+	return c.CreateBucketWithOptionsWithContext(context.Background(), bucket, opts)
+}
+
+// BucketExists returns true if the bucket exists.
+func (c backgroundClient) BucketExists(bucket string) (bool, error) {
+	return c.BucketExistsWithContext(context.Background(), bucket)
+}
+
+// DeleteBucket deletes the bucket emptying it first if force is set.
+func (c backgroundClient) DeleteBucket(bucket string, force bool) error {
+	return c.DeleteBucketWithContext(context.Background(), bucket, force)
+}
+
+// GetLifecycleRules returns lifecycle rules of the bucket.
+func (c backgroundClient) GetLifecycleRules(bucket string) ([]LifecycleRule, error) {
+	return c.GetLifecycleRulesWithContext(context.Background(), bucket)
+}
+
+// SetLifecycleRules replaces lifecycle rules of the bucket.
+func (c backgroundClient) SetLifecycleRules(bucket string, rules []LifecycleRule) error {
+	return c.SetLifecycleRulesWithContext(context.Background(), bucket, rules)
+}
+
+// GetBucketPolicy returns a JSON policy of the bucket.
+func (c backgroundClient) GetBucketPolicy(bucket string) (string, error) {
+	return c.GetBucketPolicyWithContext(context.Background(), bucket)
+}
+
+// SetBucketPolicy replaces a JSON policy of the bucket.
+func (c backgroundClient) SetBucketPolicy(bucket, policy string) error {
+	return c.SetBucketPolicyWithContext(context.Background(), bucket, policy)
+}
+
+// Exists returns true if S3 object exists.
+func (c backgroundClient) Exists(obj S3Path) (bool, error) {
+	return c.ExistsWithContext(context.Background(), obj)
+}
+
+// GetSize returns a size in bytes of the object.
+func (c backgroundClient) GetSize(obj S3Path, callerPays bool) (int64, error) {
+	return c.GetSizeWithContext(context.Background(), obj, callerPays)
+}
+
+// Stat returns all the object attributes.
+func (c backgroundClient) Stat(obj S3Path, callerPays bool) (ObjectInfo, error) {
+	return c.StatWithContext(context.Background(), obj, callerPays)
+}
+
+// GetTags returns tags of the object.
+func (c backgroundClient) GetTags(obj S3Path) (map[string]string, error) {
+	return c.GetTagsWithContext(context.Background(), obj)
+}
+
+// SetTags replaces tags of the object.
+func (c backgroundClient) SetTags(obj S3Path, tags map[string]string) error {
+	return c.SetTagsWithContext(context.Background(), obj, tags)
+}
+
+// GetObject returns an S3 object in a byte array view.
+func (c backgroundClient) GetObject(obj S3Path, callerPays bool) ([]byte, error) {
+	return c.GetObjectWithContext(context.Background(), obj, callerPays)
+}
+
+// GetObjectStream returns a reader of an S3 object content.
+func (c backgroundClient) GetObjectStream(obj S3Path, callerPays bool, byteRange *ByteRange) (io.ReadCloser, error) {
+	return c.GetObjectStreamWithContext(context.Background(), obj, callerPays, byteRange)
+}
+
+// DownloadFile downloads an object to a local file by byte ranges fetched in parallel.
+func (c backgroundClient) DownloadFile(obj S3Path, localPath string, opts DownloadFileOptions) error {
+	return c.DownloadFileWithContext(context.Background(), obj, localPath, opts)
+}
+
+// GetObjectIfChanged returns an S3 object content and attributes if the conditions are met.
+func (c backgroundClient) GetObjectIfChanged(obj S3Path, callerPays bool, cond GetConditions) ([]byte, ObjectInfo, error) {
+	return c.GetObjectIfChangedWithContext(context.Background(), obj, callerPays, cond)
+}
+
+// PutObject uploads a body to the S3 path using default upload options.
+func (c backgroundClient) PutObject(obj S3Path, body io.Reader) error {
+	return c.PutObjectWithContext(context.Background(), obj, body)
+}
+
+// Upload uploads a body to the S3 path.
+func (c backgroundClient) Upload(obj S3Path, body io.Reader, opts UploadOptions) error {
+	return c.UploadWithContext(context.Background(), obj, body, opts)
+}
+
+// UploadFile uploads a local file resuming its interrupted multipart upload if any.
+func (c backgroundClient) UploadFile(localPath string, obj S3Path, opts UploadFileOptions) error {
+	return c.UploadFileWithContext(context.Background(), localPath, obj, opts)
+}
+
+// Delete deletes an S3 object.
+func (c backgroundClient) Delete(obj S3Path) error {
+	return c.DeleteWithContext(context.Background(), obj)
+}
+
+// DeleteMany deletes objects by batches and returns objects that were not deleted.
+func (c backgroundClient) DeleteMany(objs []S3Path) ([]DeleteFailure, error) {
+	return c.DeleteManyWithContext(context.Background(), objs)
+}
+
+// DeletePrefix deletes all objects which keys start with the prefix and returns a number of objects deleted.
+func (c backgroundClient) DeletePrefix(prefix S3Path) (int, error) {
+	return c.DeletePrefixWithContext(context.Background(), prefix)
+}
+
+// Copy copies source to destination.
+func (c backgroundClient) Copy(src, dst S3Path, validateEtag, callerPays bool) error {
+	return c.CopyWithContext(context.Background(), src, dst, validateEtag, callerPays)
+}
+
+// CopyObject copies source to destination using the options provided.
+func (c backgroundClient) CopyObject(src, dst S3Path, opts CopyOptions) error {
+	return c.CopyObjectWithContext(context.Background(), src, dst, opts)
+}
+
+// IsSrcNewer returns true if source exist and newer thad destination, or when destination does not exist.
+func (c backgroundClient) IsSrcNewer(src, dst S3Path, callerPays bool) (bool, error) {
+	return c.IsSrcNewerWithContext(context.Background(), src, dst, callerPays)
+}
+
+// Sync makes objects of the dst prefix the same as the ones of the src prefix.
+func (c backgroundClient) Sync(src, dst S3Path, opts SyncOptions) (*SyncReport, error) {
+	return c.SyncWithContext(context.Background(), src, dst, opts)
+}
+
+// SyncFromDir makes objects of the dst prefix the same as files of the local directory.
+func (c backgroundClient) SyncFromDir(localDir string, dst S3Path, opts SyncOptions) (*SyncReport, error) {
+	return c.SyncFromDirWithContext(context.Background(), localDir, dst, opts)
+}
+
+// GetPresignedURL returns an S3 presigned URL for the given object.
+func (c backgroundClient) GetPresignedURL(obj S3Path, duration time.Duration) (string, error) {
+	return c.GetPresignedURLWithContext(context.Background(), obj, duration)
+}
+
+// GetPresignedPutURL returns an S3 presigned URL to upload the object.
+func (c backgroundClient) GetPresignedPutURL(
+	obj S3Path,
+	duration time.Duration,
+	contentType, contentMD5 string,
+) (string, error) {
+	return c.GetPresignedPutURLWithContext(context.Background(), obj, duration, contentType, contentMD5)
+}
+
+// GetPresignedDeleteURL returns an S3 presigned URL to delete the object.
+func (c backgroundClient) GetPresignedDeleteURL(obj S3Path, duration time.Duration) (string, error) {
+	return c.GetPresignedDeleteURLWithContext(context.Background(), obj, duration)
+}
+
+// GetPresignedPost returns a URL and form fields of a browser POST upload.
+func (c backgroundClient) GetPresignedPost(obj S3Path, duration time.Duration, opts PostPolicyOptions) (*PresignedPost, error) {
+	return c.GetPresignedPostWithContext(context.Background(), obj, duration, opts)
+}
+
+// GetETag returns an ETag of the object.
+func (c backgroundClient) GetETag(obj S3Path) (string, error) {
+	return c.GetETagWithContext(context.Background(), obj)
+}
+
+// List returns an iterator over objects which keys start with the prefix.
+func (c backgroundClient) List(prefix S3Path, delimiter string) *ObjectIterator {
+	return c.ListWithContext(context.Background(), prefix, delimiter)
+}
+
+// ListVersions returns an iterator over object versions of the prefix.
+func (c backgroundClient) ListVersions(prefix S3Path) *ObjectIterator {
+	return c.ListVersionsWithContext(context.Background(), prefix)
+}
+
+// ListIncompleteUploads returns all incomplete multipart uploads of the bucket.
+func (c backgroundClient) ListIncompleteUploads(bucket, prefix string) ([]IncompleteUpload, error) {
+	return c.ListIncompleteUploadsWithContext(context.Background(), bucket, prefix)
+}
+
+// AbortUpload aborts an incomplete multipart upload.
+func (c backgroundClient) AbortUpload(upload IncompleteUpload) error {
+	return c.AbortUploadWithContext(context.Background(), upload)
+}
+
+// AbortStaleUploads aborts incomplete multipart uploads initiated more than olderThan ago.
+func (c backgroundClient) AbortStaleUploads(bucket string, olderThan time.Duration) (int, error) {
+	return c.AbortStaleUploadsWithContext(context.Background(), bucket, olderThan)
+}
+
+// backgroundBucketClient implements BucketClient methods without a context by calling
+// their context-aware counterparts with context.Background().
+type backgroundBucketClient struct {
+	BucketClientCtx
+}
+
+// Exists returns true if S3 object exists.
+func (c backgroundBucketClient) Exists(key string) (bool, error) {
+	return c.ExistsWithContext(context.Background(), key)
+}
+
+// GetSize returns a size in bytes of the object.
+func (c backgroundBucketClient) GetSize(key string, callerPays bool) (int64, error) {
+	return c.GetSizeWithContext(context.Background(), key, callerPays)
+}
+
+// Stat returns all the object attributes.
+func (c backgroundBucketClient) Stat(key string, callerPays bool) (ObjectInfo, error) {
+	return c.StatWithContext(context.Background(), key, callerPays)
+}
+
+// GetTags returns tags of the object.
+func (c backgroundBucketClient) GetTags(key string) (map[string]string, error) {
+	return c.GetTagsWithContext(context.Background(), key)
+}
+
+// SetTags replaces tags of the object.
+func (c backgroundBucketClient) SetTags(key string, tags map[string]string) error {
+	return c.SetTagsWithContext(context.Background(), key, tags)
+}
+
+// GetObject returns an S3 object in a byte array view.
+func (c backgroundBucketClient) GetObject(key string, callerPays bool) ([]byte, error) {
+	return c.GetObjectWithContext(context.Background(), key, callerPays)
+}
+
+// GetObjectStream returns a reader of an S3 object content.
+func (c backgroundBucketClient) GetObjectStream(key string, callerPays bool, byteRange *ByteRange) (io.ReadCloser, error) {
+	return c.GetObjectStreamWithContext(context.Background(), key, callerPays, byteRange)
+}
+
+// DownloadFile downloads an object to a local file by byte ranges fetched in parallel.
+func (c backgroundBucketClient) DownloadFile(key, localPath string, opts DownloadFileOptions) error {
+	return c.DownloadFileWithContext(context.Background(), key, localPath, opts)
+}
+
+// GetObjectIfChanged returns an S3 object content and attributes if the conditions are met.
+func (c backgroundBucketClient) GetObjectIfChanged(key string, callerPays bool, cond GetConditions) ([]byte, ObjectInfo, error) {
+	return c.GetObjectIfChangedWithContext(context.Background(), key, callerPays, cond)
+}
+
+// PutObject uploads a body to the key using default upload options.
+func (c backgroundBucketClient) PutObject(key string, body io.Reader) error {
+	return c.PutObjectWithContext(context.Background(), key, body)
+}
+
+// Upload uploads a body to the key.
+func (c backgroundBucketClient) Upload(key string, body io.Reader, opts UploadOptions) error {
+	return c.UploadWithContext(context.Background(), key, body, opts)
+}
+
+// UploadFile uploads a local file resuming its interrupted multipart upload if any.
+func (c backgroundBucketClient) UploadFile(localPath, key string, opts UploadFileOptions) error {
+	return c.UploadFileWithContext(context.Background(), localPath, key, opts)
+}
+
+// Delete deletes an S3 object.
+func (c backgroundBucketClient) Delete(key string) error {
+	return c.DeleteWithContext(context.Background(), key)
+}
+
+// DeleteMany deletes objects by batches and returns objects that were not deleted.
+func (c backgroundBucketClient) DeleteMany(keys []string) ([]DeleteFailure, error) {
+	return c.DeleteManyWithContext(context.Background(), keys)
+}
+
+// DeletePrefix deletes all objects which keys start with the prefix and returns a number of objects deleted.
+func (c backgroundBucketClient) DeletePrefix(prefix string) (int, error) {
+	return c.DeletePrefixWithContext(context.Background(), prefix)
+}
+
+// Copy copies source to destination.
+func (c backgroundBucketClient) Copy(src, dst string, validateEtag, callerPays bool) error {
+	return c.CopyWithContext(context.Background(), src, dst, validateEtag, callerPays)
+}
+
+// CopyObject copies source to destination using the options provided.
+func (c backgroundBucketClient) CopyObject(src, dst string, opts CopyOptions) error {
+	return c.CopyObjectWithContext(context.Background(), src, dst, opts)
+}
+
+// CopyTo copies an object of the bucket to the destination bucket.
+func (c backgroundBucketClient) CopyTo(dstBucket BucketClient, srcKey, dstKey string) error {
+	return c.CopyToWithContext(context.Background(), dstBucket, srcKey, dstKey)
+}
+
+// CopyObjectTo copies an object of the bucket to the destination bucket using the options provided.
+func (c backgroundBucketClient) CopyObjectTo(dstBucket BucketClient, srcKey, dstKey string, opts CopyOptions) error {
+	return c.CopyObjectToWithContext(context.Background(), dstBucket, srcKey, dstKey, opts)
+}
+
+// IsSrcNewer returns true if source exist and newer thad destination, or when destination does not exist.
+func (c backgroundBucketClient) IsSrcNewer(src, dst string, callerPays bool) (bool, error) {
+	return c.IsSrcNewerWithContext(context.Background(), src, dst, callerPays)
+}
+
+// Sync makes objects of the dstPrefix the same as the ones of the srcPrefix.
+func (c backgroundBucketClient) Sync(srcPrefix, dstPrefix string, opts SyncOptions) (*SyncReport, error) {
+	return c.SyncWithContext(context.Background(), srcPrefix, dstPrefix, opts)
+}
+
+// SyncFromDir makes objects of the dstPrefix the same as files of the local directory.
+func (c backgroundBucketClient) SyncFromDir(localDir, dstPrefix string, opts SyncOptions) (*SyncReport, error) {
+	return c.SyncFromDirWithContext(context.Background(), localDir, dstPrefix, opts)
+}
+
+// GetPresignedURL returns an S3 presigned URL for the given key.
+func (c backgroundBucketClient) GetPresignedURL(key string, duration time.Duration) (string, error) {
+	return c.GetPresignedURLWithContext(context.Background(), key, duration)
+}
+
+// GetPresignedPutURL returns an S3 presigned URL to upload the object.
+func (c backgroundBucketClient) GetPresignedPutURL(
+	key string,
+	duration time.Duration,
+	contentType, contentMD5 string,
+) (string, error) {
+	return c.GetPresignedPutURLWithContext(context.Background(), key, duration, contentType, contentMD5)
+}
+
+// GetPresignedDeleteURL returns an S3 presigned URL to delete the object.
+func (c backgroundBucketClient) GetPresignedDeleteURL(key string, duration time.Duration) (string, error) {
+	return c.GetPresignedDeleteURLWithContext(context.Background(), key, duration)
+}
+
+// GetPresignedPost returns a URL and form fields of a browser POST upload.
+func (c backgroundBucketClient) GetPresignedPost(key string, duration time.Duration, opts PostPolicyOptions) (*PresignedPost, error) {
+	return c.GetPresignedPostWithContext(context.Background(), key, duration, opts)
+}
+
+// GetETag returns an ETag of the object.
+func (c backgroundBucketClient) GetETag(key string) (string, error) {
+	return c.GetETagWithContext(context.Background(), key)
+}
+
+// List returns an iterator over objects of the bucket which keys start with the prefix.
+func (c backgroundBucketClient) List(prefix, delimiter string) *ObjectIterator {
+	return c.ListWithContext(context.Background(), prefix, delimiter)
+}
+
+// ListVersions returns an iterator over object versions of the prefix.
+func (c backgroundBucketClient) ListVersions(prefix string) *ObjectIterator {
+	return c.ListVersionsWithContext(context.Background(), prefix)
+}
+
+// ListIncompleteUploads returns all incomplete multipart uploads of the bucket.
+func (c backgroundBucketClient) ListIncompleteUploads(prefix string) ([]IncompleteUpload, error) {
+	return c.ListIncompleteUploadsWithContext(context.Background(), prefix)
+}
+
+// AbortStaleUploads aborts incomplete multipart uploads initiated more than olderThan ago.
+func (c backgroundBucketClient) AbortStaleUploads(olderThan time.Duration) (int, error) {
+	return c.AbortStaleUploadsWithContext(context.Background(), olderThan)
+}
+
+// BucketExists returns true if the bucket exists.
+func (c backgroundBucketClient) BucketExists() (bool, error) {
+	return c.BucketExistsWithContext(context.Background())
+}
+
+// DeleteBucket deletes the bucket emptying it first if force is set.
+func (c backgroundBucketClient) DeleteBucket(force bool) error {
+	return c.DeleteBucketWithContext(context.Background(), force)
+}
+
+// GetLifecycleRules returns lifecycle rules of the bucket.
+func (c backgroundBucketClient) GetLifecycleRules() ([]LifecycleRule, error) {
+	return c.GetLifecycleRulesWithContext(context.Background())
+}
+
+// SetLifecycleRules replaces lifecycle rules of the bucket.
+func (c backgroundBucketClient) SetLifecycleRules(rules []LifecycleRule) error {
+	return c.SetLifecycleRulesWithContext(context.Background(), rules)
+}
+
+// GetBucketPolicy returns a JSON policy of the bucket.
+func (c backgroundBucketClient) GetBucketPolicy() (string, error) {
+	return c.GetBucketPolicyWithContext(context.Background())
+}
+
+// SetBucketPolicy replaces a JSON policy of the bucket.
+func (c backgroundBucketClient) SetBucketPolicy(policy string) error {
+	return c.SetBucketPolicyWithContext(context.Background(), policy)
+}
@@ -0,0 +1,333 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// regionNoLocationConstraint is a region which buckets are created without a location constraint.
+	regionNoLocationConstraint = "us-east-1"
+)
+
+// CreateBucketOptions contains parameters of a new bucket.
+type CreateBucketOptions struct {
+	// Region is a region of the bucket, the client one is used when empty.
+	Region string
+	// ACL is a canned ACL of the bucket, e.g. s3.BucketCannedACLPrivate. No ACL is set when empty.
+	ACL string
+	// ObjectLock enables S3 Object Lock on the bucket, it enables versioning as well.
+	ObjectLock bool
+	// Versioning enables versioning on the bucket.
+	Versioning bool
+}
+
+// LifecycleRule is a bucket lifecycle rule applied to objects which keys start with the prefix.
+// Zero days disable the corresponding action.
+type LifecycleRule struct {
+	ID      string
+	Prefix  string
+	Enabled bool
+	// ExpirationDays deletes objects the days after their creation.
+	ExpirationDays int
+	// NoncurrentExpirationDays deletes previous versions of objects the days after they become noncurrent.
+	NoncurrentExpirationDays int
+	// AbortIncompleteUploadDays aborts incomplete multipart uploads the days after their initiation.
+	AbortIncompleteUploadDays int
+	// TransitionDays moves objects to TransitionStorageClass the days after their creation.
+	TransitionDays         int
+	TransitionStorageClass string
+}
+
+// CreateBucketWithContext creates a bucket in the client region. A bucket that is already owned
+// by the caller is not an error, ErrBucketAlreadyExists is returned if it is owned by another account.
+func (c *s3Client) CreateBucketWithContext(ctx context.Context, bucket string) (string, error) {
+	return c.CreateBucketWithOptionsWithContext(ctx, bucket, CreateBucketOptions{})
+}
+
+// CreateBucketWithOptionsWithContext creates a bucket with the options provided and returns its location.
+// Versioning is enabled on a bucket that is already owned by the caller as well.
+func (c *s3Client) CreateBucketWithOptionsWithContext(
+	ctx context.Context,
+	bucket string,
+	opts CreateBucketOptions,
+) (string, error) {
+	params := &s3.CreateBucketInput{
+		Bucket: aws.String(bucket),
+	}
+
+	region := opts.Region
+	if region == "" {
+		region = aws.StringValue(c.awsS3.Config.Region)
+	}
+	if region != "" && region != regionNoLocationConstraint {
+		params.CreateBucketConfiguration = &s3.CreateBucketConfiguration{
+			LocationConstraint: aws.String(region),
+		}
+	}
+
+	if opts.ACL != "" {
+		params.ACL = aws.String(opts.ACL)
+	}
+	if opts.ObjectLock {
+		params.ObjectLockEnabledForBucket = aws.Bool(true)
+	}
+
+	// the request is sent to the bucket region endpoint.
+	awsS3, err := c.regionClient(region)
+	if err != nil {
+		return "", fmt.Errorf("error creating a client of region %v: %w", region, err)
+	}
+
+	location := "/" + bucket
+	resp, err := awsS3.CreateBucketWithContext(ctx, params)
+	if err != nil && errorCode(err) != awsErrBucketOwned {
+		return "", newError(S3Path{Bucket: bucket}, err)
+	}
+	if err == nil && resp.Location != nil {
+		location = *resp.Location
+	}
+
+	if opts.Versioning {
+		_, err = awsS3.PutBucketVersioningWithContext(ctx, &s3.PutBucketVersioningInput{
+			Bucket: aws.String(bucket),
+			VersioningConfiguration: &s3.VersioningConfiguration{
+				Status: aws.String(s3.BucketVersioningStatusEnabled),
+			},
+			ExpectedBucketOwner: c.opts.bucketOwner(),
+		})
+		if err != nil {
+			return "", fmt.Errorf("error enabling versioning: %w", newError(S3Path{Bucket: bucket}, err))
+		}
+	}
+
+	return location, nil
+}
+
+// regionClient returns an SDK client sending requests to the region endpoint. It is the client itself
+// if the region is the client one, a client with the same configuration is created otherwise.
+func (c *s3Client) regionClient(region string) (*s3.S3, error) {
+	if region == "" || region == aws.StringValue(c.awsS3.Config.Region) {
+		return c.awsS3, nil
+	}
+
+	sess, err := session.NewSession(c.awsS3.Config.Copy(aws.NewConfig().WithRegion(region)))
+	if err != nil {
+		return nil, err
+	}
+
+	return s3.New(sess), nil
+}
+
+// BucketExistsWithContext returns true if the bucket exists and is accessible by the client.
+func (c *s3Client) BucketExistsWithContext(ctx context.Context, bucket string) (bool, error) {
+	_, err := c.awsS3.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		err = newError(S3Path{Bucket: bucket}, err)
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DeleteBucketWithContext deletes the bucket. A bucket must be empty to be deleted, so force deletes
+// all its object versions, delete markers and incomplete multipart uploads first.
+func (c *s3Client) DeleteBucketWithContext(ctx context.Context, bucket string, force bool) error {
+	if force {
+		if err := emptyBucket(ctx, c, bucket, c.deleteBatch); err != nil {
+			return err
+		}
+	}
+
+	_, err := c.awsS3.DeleteBucketWithContext(ctx, &s3.DeleteBucketInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting bucket: %w", newError(S3Path{Bucket: bucket}, err))
+	}
+
+	return nil
+}
+
+// GetLifecycleRulesWithContext returns lifecycle rules of the bucket, no rules are returned if none is set.
+func (c *s3Client) GetLifecycleRulesWithContext(ctx context.Context, bucket string) ([]LifecycleRule, error) {
+	resp, err := c.awsS3.GetBucketLifecycleConfigurationWithContext(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		if errorCode(err) == awsErrNoSuchLifecycle {
+			return []LifecycleRule{}, nil
+		}
+
+		return nil, fmt.Errorf("error querying lifecycle rules: %w", newError(S3Path{Bucket: bucket}, err))
+	}
+
+	rules := make([]LifecycleRule, 0, len(resp.Rules))
+	for _, r := range resp.Rules {
+		rule := LifecycleRule{
+			ID:      aws.StringValue(r.ID),
+			Prefix:  aws.StringValue(r.Prefix), //nolint:staticcheck // rules created by old tools use it.
+			Enabled: aws.StringValue(r.Status) == s3.ExpirationStatusEnabled,
+		}
+		if r.Filter != nil && r.Filter.Prefix != nil {
+			rule.Prefix = *r.Filter.Prefix
+		}
+		if r.Expiration != nil {
+			rule.ExpirationDays = int(aws.Int64Value(r.Expiration.Days))
+		}
+		if r.NoncurrentVersionExpiration != nil {
+			rule.NoncurrentExpirationDays = int(aws.Int64Value(r.NoncurrentVersionExpiration.NoncurrentDays))
+		}
+		if r.AbortIncompleteMultipartUpload != nil {
+			rule.AbortIncompleteUploadDays = int(aws.Int64Value(r.AbortIncompleteMultipartUpload.DaysAfterInitiation))
+		}
+		if len(r.Transitions) > 0 {
+			rule.TransitionDays = int(aws.Int64Value(r.Transitions[0].Days))
+			rule.TransitionStorageClass = aws.StringValue(r.Transitions[0].StorageClass)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// SetLifecycleRulesWithContext replaces lifecycle rules of the bucket, empty rules delete the lifecycle configuration.
+func (c *s3Client) SetLifecycleRulesWithContext(ctx context.Context, bucket string, rules []LifecycleRule) error {
+	if len(rules) == 0 {
+		_, err := c.awsS3.DeleteBucketLifecycleWithContext(ctx, &s3.DeleteBucketLifecycleInput{
+			Bucket:              aws.String(bucket),
+			ExpectedBucketOwner: c.opts.bucketOwner(),
+		})
+		if err != nil {
+			return fmt.Errorf("error deleting lifecycle rules: %w", newError(S3Path{Bucket: bucket}, err))
+		}
+
+		return nil
+	}
+
+	awsRules := make([]*s3.LifecycleRule, len(rules))
+	for i, rule := range rules {
+		r := &s3.LifecycleRule{
+			ID:     aws.String(rule.ID),
+			Status: aws.String(s3.ExpirationStatusDisabled),
+			Filter: &s3.LifecycleRuleFilter{
+				Prefix: aws.String(rule.Prefix),
+			},
+		}
+		if rule.Enabled {
+			r.Status = aws.String(s3.ExpirationStatusEnabled)
+		}
+		if rule.ExpirationDays > 0 {
+			r.Expiration = &s3.LifecycleExpiration{
+				Days: aws.Int64(int64(rule.ExpirationDays)),
+			}
+		}
+		if rule.NoncurrentExpirationDays > 0 {
+			r.NoncurrentVersionExpiration = &s3.NoncurrentVersionExpiration{
+				NoncurrentDays: aws.Int64(int64(rule.NoncurrentExpirationDays)),
+			}
+		}
+		if rule.AbortIncompleteUploadDays > 0 {
+			r.AbortIncompleteMultipartUpload = &s3.AbortIncompleteMultipartUpload{
+				DaysAfterInitiation: aws.Int64(int64(rule.AbortIncompleteUploadDays)),
+			}
+		}
+		if rule.TransitionStorageClass != "" {
+			r.Transitions = []*s3.Transition{{
+				Days:         aws.Int64(int64(rule.TransitionDays)),
+				StorageClass: aws.String(rule.TransitionStorageClass),
+			}}
+		}
+
+		awsRules[i] = r
+	}
+
+	_, err := c.awsS3.PutBucketLifecycleConfigurationWithContext(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+		LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
+			Rules: awsRules,
+		},
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("error setting lifecycle rules: %w", newError(S3Path{Bucket: bucket}, err))
+	}
+
+	return nil
+}
+
+// GetBucketPolicyWithContext returns a JSON policy of the bucket or an empty string if none is set.
+func (c *s3Client) GetBucketPolicyWithContext(ctx context.Context, bucket string) (string, error) {
+	resp, err := c.awsS3.GetBucketPolicyWithContext(ctx, &s3.GetBucketPolicyInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		if errorCode(err) == awsErrNoSuchBucketPolicy {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("error querying bucket policy: %w", newError(S3Path{Bucket: bucket}, err))
+	}
+
+	return aws.StringValue(resp.Policy), nil
+}
+
+// SetBucketPolicyWithContext replaces a JSON policy of the bucket, an empty policy deletes the bucket one.
+func (c *s3Client) SetBucketPolicyWithContext(ctx context.Context, bucket, policy string) error {
+	if policy == "" {
+		_, err := c.awsS3.DeleteBucketPolicyWithContext(ctx, &s3.DeleteBucketPolicyInput{
+			Bucket:              aws.String(bucket),
+			ExpectedBucketOwner: c.opts.bucketOwner(),
+		})
+		if err != nil {
+			return fmt.Errorf("error deleting bucket policy: %w", newError(S3Path{Bucket: bucket}, err))
+		}
+
+		return nil
+	}
+
+	_, err := c.awsS3.PutBucketPolicyWithContext(ctx, &s3.PutBucketPolicyInput{
+		Bucket:              aws.String(bucket),
+		Policy:              aws.String(policy),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("error setting bucket policy: %w", newError(S3Path{Bucket: bucket}, err))
+	}
+
+	return nil
+}
+
+// emptyBucket deletes all object versions, delete markers and incomplete multipart uploads of the bucket
+// using the client provided. Objects of a bucket without versioning are listed as null versions.
+func emptyBucket(ctx context.Context, client S3ClientCtx, bucket string, deleteBatch deleteBatchFunc) error {
+	listVersions := func(ctx context.Context, prefix S3Path, _ string) *ObjectIterator {
+		return client.ListVersionsWithContext(ctx, prefix)
+	}
+
+	if _, err := deletePrefix(ctx, S3Path{Bucket: bucket}, listVersions, deleteBatch); err != nil {
+		return fmt.Errorf("error emptying bucket %v : %w", bucket, err)
+	}
+
+	if _, err := abortStaleUploads(ctx, client, bucket, 0); err != nil {
+		return fmt.Errorf("error emptying bucket %v : %w", bucket, err)
+	}
+
+	return nil
+}
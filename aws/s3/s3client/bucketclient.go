@@ -2,30 +2,34 @@
 package s3client
 
 import (
+	"context"
+	"io"
 	"time"
 
 	"github.com/aws/aws-sdk-go/service/s3"
 )
 
 type bucketClient struct {
+	backgroundBucketClient
+
 	client S3Client
 	bucket string
 }
 
 // NewBucketClient returns a new S3Bucket
-func NewBucketClient(s3 *s3.S3, bucket string) BucketClient {
-	return &bucketClient{
-		client: NewClientFromS3(s3),
-		bucket: bucket,
-	}
+func NewBucketClient(s3 *s3.S3, bucket string, opts ...ClientOption) BucketClient {
+	return NewBucketClientWithClient(NewClientFromS3(s3, opts...), bucket)
 }
 
 // NewBucketClientWithClient creates a new bucket's client using S3Client interface.
 func NewBucketClientWithClient(client S3Client, bucket string) BucketClient {
-	return &bucketClient{
+	c := &bucketClient{
 		client: client,
 		bucket: bucket,
 	}
+	c.backgroundBucketClient = backgroundBucketClient{c}
+
+	return c
 }
 
 // Connection returns an AWS S3 connection that was used when creating the object.
@@ -33,14 +37,84 @@ func (c *bucketClient) Client() S3Client {
 	return c.client
 }
 
+// Bucket returns a name of the bucket.
+func (c *bucketClient) Bucket() string {
+	return c.bucket
+}
+
 // CreateBucket ...
 func (c *bucketClient) CreateBucket(bucket string) (string, error) {
 	return c.client.CreateBucket(bucket)
 }
 
-// Get returns an S3 object in a byte array view.
-func (c *bucketClient) GetObject(key string, callerPays bool) ([]byte, error) {
-	return c.client.GetObject(
+// GetObjectWithContext returns an S3 object in a byte array view.
+func (c *bucketClient) GetObjectWithContext(ctx context.Context, key string, callerPays bool) ([]byte, error) {
+	return c.client.GetObjectWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    key,
+		},
+		callerPays,
+	)
+}
+
+// GetObjectStreamWithContext returns a reader of an S3 object content. A nil byteRange means the whole object.
+// The caller is responsible for closing the reader returned.
+func (c *bucketClient) GetObjectStreamWithContext(
+	ctx context.Context,
+	key string,
+	callerPays bool,
+	byteRange *ByteRange,
+) (io.ReadCloser, error) {
+	return c.client.GetObjectStreamWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    key,
+		},
+		callerPays,
+		byteRange,
+	)
+}
+
+// DownloadFileWithContext downloads an object to a local file by byte ranges fetched in parallel,
+// the local file is replaced only once the whole object is downloaded and verified.
+func (c *bucketClient) DownloadFileWithContext(ctx context.Context, key, localPath string, opts DownloadFileOptions) error {
+	return c.client.DownloadFileWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    key,
+		},
+		localPath,
+		opts,
+	)
+}
+
+// GetObjectIfChangedWithContext returns an S3 object content and attributes if the conditions are met
+// or ErrNotModified if the object has not been changed.
+func (c *bucketClient) GetObjectIfChangedWithContext(
+	ctx context.Context,
+	key string,
+	callerPays bool,
+	cond GetConditions,
+) ([]byte, ObjectInfo, error) {
+	return c.client.GetObjectIfChangedWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    key,
+		},
+		callerPays,
+		cond,
+	)
+}
+
+// GetSizeWithContext returns a size in bytes of the object.
+func (c *bucketClient) GetSizeWithContext(ctx context.Context, key string, callerPays bool) (int64, error) {
+	return c.client.GetSizeWithContext(
+		ctx,
 		S3Path{
 			Bucket: c.bucket,
 			Key:    key,
@@ -49,9 +123,10 @@ func (c *bucketClient) GetObject(key string, callerPays bool) ([]byte, error) {
 	)
 }
 
-// GetSize returns a size in bytes of the object.
-func (c *bucketClient) GetSize(key string, callerPays bool) (int64, error) {
-	return c.client.GetSize(
+// StatWithContext returns all the object attributes by a single HEAD request.
+func (c *bucketClient) StatWithContext(ctx context.Context, key string, callerPays bool) (ObjectInfo, error) {
+	return c.client.StatWithContext(
+		ctx,
 		S3Path{
 			Bucket: c.bucket,
 			Key:    key,
@@ -60,19 +135,33 @@ func (c *bucketClient) GetSize(key string, callerPays bool) (int64, error) {
 	)
 }
 
-// Exists returns true if S3 object exists.
-func (c *bucketClient) Exists(key string) (bool, error) {
-	return c.client.Exists(
+// GetTagsWithContext returns tags of the object.
+func (c *bucketClient) GetTagsWithContext(ctx context.Context, key string) (map[string]string, error) {
+	return c.client.GetTagsWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    key,
+		},
+	)
+}
+
+// SetTagsWithContext replaces tags of the object by the ones provided.
+func (c *bucketClient) SetTagsWithContext(ctx context.Context, key string, tags map[string]string) error {
+	return c.client.SetTagsWithContext(
+		ctx,
 		S3Path{
 			Bucket: c.bucket,
 			Key:    key,
 		},
+		tags,
 	)
 }
 
-// Delete deletes an S3 object.
-func (c *bucketClient) Delete(key string) error {
-	return c.client.Delete(
+// ExistsWithContext returns true if S3 object exists.
+func (c *bucketClient) ExistsWithContext(ctx context.Context, key string) (bool, error) {
+	return c.client.ExistsWithContext(
+		ctx,
 		S3Path{
 			Bucket: c.bucket,
 			Key:    key,
@@ -80,10 +169,88 @@ func (c *bucketClient) Delete(key string) error {
 	)
 }
 
-// Copy copies source to destination and checks if required the result integrity
+// PutObjectWithContext uploads a body to the key using default upload options.
+func (c *bucketClient) PutObjectWithContext(ctx context.Context, key string, body io.Reader) error {
+	return c.client.PutObjectWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    key,
+		},
+		body,
+	)
+}
+
+// UploadWithContext uploads a body to the key. A body larger than a part size is uploaded
+// by a parallel multipart upload.
+func (c *bucketClient) UploadWithContext(ctx context.Context, key string, body io.Reader, opts UploadOptions) error {
+	return c.client.UploadWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    key,
+		},
+		body,
+		opts,
+	)
+}
+
+// UploadFileWithContext uploads a local file by a multipart upload resumed from a checkpoint file
+// if the previous upload of the file was interrupted.
+func (c *bucketClient) UploadFileWithContext(ctx context.Context, localPath, key string, opts UploadFileOptions) error {
+	return c.client.UploadFileWithContext(
+		ctx,
+		localPath,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    key,
+		},
+		opts,
+	)
+}
+
+// DeleteWithContext deletes an S3 object.
+func (c *bucketClient) DeleteWithContext(ctx context.Context, key string) error {
+	return c.client.DeleteWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    key,
+		},
+	)
+}
+
+// DeleteManyWithContext deletes objects by batches of up to 1000 keys per request
+// and returns objects that were not deleted.
+func (c *bucketClient) DeleteManyWithContext(ctx context.Context, keys []string) ([]DeleteFailure, error) {
+	objs := make([]S3Path, len(keys))
+	for i, key := range keys {
+		objs[i] = S3Path{
+			Bucket: c.bucket,
+			Key:    key,
+		}
+	}
+
+	return c.client.DeleteManyWithContext(ctx, objs)
+}
+
+// DeletePrefixWithContext deletes all objects which keys start with the prefix
+// and returns a number of objects deleted. An empty prefix is an error.
+func (c *bucketClient) DeletePrefixWithContext(ctx context.Context, prefix string) (int, error) {
+	return c.client.DeletePrefixWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    prefix,
+		},
+	)
+}
+
+// CopyWithContext copies source to destination and checks if required the result integrity
 // by comparing an ETag of source and destination.
-func (c *bucketClient) Copy(src, dst string, validateEtag, callerPays bool) error {
-	return c.client.Copy(
+func (c *bucketClient) CopyWithContext(ctx context.Context, src, dst string, validateEtag, callerPays bool) error {
+	return c.client.CopyWithContext(
+		ctx,
 		S3Path{
 			Bucket: c.bucket,
 			Key:    src,
@@ -97,9 +264,59 @@ func (c *bucketClient) Copy(src, dst string, validateEtag, callerPays bool) erro
 	)
 }
 
-// IsSrcNewer returns true if source exist and newer thad destination, or when destination does not exist.
-func (c *bucketClient) IsSrcNewer(src, dst string, callerPays bool) (bool, error) {
-	return c.client.IsSrcNewer(
+// CopyObjectWithContext copies source to destination using the options provided.
+func (c *bucketClient) CopyObjectWithContext(ctx context.Context, src, dst string, opts CopyOptions) error {
+	return c.client.CopyObjectWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    src,
+		},
+		S3Path{
+			Bucket: c.bucket,
+			Key:    dst,
+		},
+		opts,
+	)
+}
+
+// CopyToWithContext copies an object of the bucket to the destination bucket.
+func (c *bucketClient) CopyToWithContext(ctx context.Context, dstBucket BucketClient, srcKey, dstKey string) error {
+	return c.CopyObjectToWithContext(ctx, dstBucket, srcKey, dstKey, CopyOptions{})
+}
+
+// CopyObjectToWithContext copies an object of the bucket to the destination bucket using the options provided.
+// The copy is made by the destination bucket client, the source attributes are queried by this bucket client,
+// so the buckets may be in different regions.
+func (c *bucketClient) CopyObjectToWithContext(
+	ctx context.Context,
+	dstBucket BucketClient,
+	srcKey, dstKey string,
+	opts CopyOptions,
+) error {
+	dstClient := dstBucket.Client()
+	if opts.SourceClient == nil && dstClient != c.client {
+		opts.SourceClient = c.client
+	}
+
+	return dstClient.CopyObjectWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    srcKey,
+		},
+		S3Path{
+			Bucket: dstBucket.Bucket(),
+			Key:    dstKey,
+		},
+		opts,
+	)
+}
+
+// IsSrcNewerWithContext returns true if source exist and newer thad destination, or when destination does not exist.
+func (c *bucketClient) IsSrcNewerWithContext(ctx context.Context, src, dst string, callerPays bool) (bool, error) {
+	return c.client.IsSrcNewerWithContext(
+		ctx,
 		S3Path{
 			Bucket: c.bucket,
 			Key:    src,
@@ -112,9 +329,39 @@ func (c *bucketClient) IsSrcNewer(src, dst string, callerPays bool) (bool, error
 	)
 }
 
-// GetPresignedURL returns an S3 presigned URL for the given key.
-func (c *bucketClient) GetPresignedURL(key string, duration time.Duration) (string, error) {
-	return c.client.GetPresignedURL(
+// SyncWithContext makes objects of the dstPrefix the same as the ones of the srcPrefix.
+func (c *bucketClient) SyncWithContext(ctx context.Context, srcPrefix, dstPrefix string, opts SyncOptions) (*SyncReport, error) {
+	return c.client.SyncWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    srcPrefix,
+		},
+		S3Path{
+			Bucket: c.bucket,
+			Key:    dstPrefix,
+		},
+		opts,
+	)
+}
+
+// SyncFromDirWithContext makes objects of the dstPrefix the same as files of the local directory.
+func (c *bucketClient) SyncFromDirWithContext(ctx context.Context, localDir, dstPrefix string, opts SyncOptions) (*SyncReport, error) {
+	return c.client.SyncFromDirWithContext(
+		ctx,
+		localDir,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    dstPrefix,
+		},
+		opts,
+	)
+}
+
+// GetPresignedURLWithContext returns an S3 presigned URL for the given key.
+func (c *bucketClient) GetPresignedURLWithContext(ctx context.Context, key string, duration time.Duration) (string, error) {
+	return c.client.GetPresignedURLWithContext(
+		ctx,
 		S3Path{
 			Bucket: c.bucket,
 			Key:    key,
@@ -123,12 +370,130 @@ func (c *bucketClient) GetPresignedURL(key string, duration time.Duration) (stri
 	)
 }
 
-// GetPresignedURL returns an S3 presigned URL for the given key.
-func (c *bucketClient) GetETag(key string) (string, error) {
-	return c.client.GetETag(
+// GetPresignedPutURLWithContext returns an S3 presigned URL to upload the object by a PUT request.
+// Non-empty contentType and base64 encoded contentMD5 must be sent as the request headers with the same values.
+func (c *bucketClient) GetPresignedPutURLWithContext(
+	ctx context.Context,
+	key string,
+	duration time.Duration,
+	contentType, contentMD5 string,
+) (string, error) {
+	return c.client.GetPresignedPutURLWithContext(
+		ctx,
 		S3Path{
 			Bucket: c.bucket,
 			Key:    key,
 		},
+		duration,
+		contentType,
+		contentMD5,
+	)
+}
+
+// GetPresignedDeleteURLWithContext returns an S3 presigned URL to delete the object by a DELETE request.
+func (c *bucketClient) GetPresignedDeleteURLWithContext(ctx context.Context, key string, duration time.Duration) (string, error) {
+	return c.client.GetPresignedDeleteURLWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    key,
+		},
+		duration,
 	)
 }
+
+// GetPresignedPostWithContext returns a URL and form fields of a browser POST upload to the key.
+func (c *bucketClient) GetPresignedPostWithContext(
+	ctx context.Context,
+	key string,
+	duration time.Duration,
+	opts PostPolicyOptions,
+) (*PresignedPost, error) {
+	return c.client.GetPresignedPostWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    key,
+		},
+		duration,
+		opts,
+	)
+}
+
+// GetETagWithContext returns an ETag of the object.
+func (c *bucketClient) GetETagWithContext(ctx context.Context, key string) (string, error) {
+	return c.client.GetETagWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    key,
+		},
+	)
+}
+
+// ListWithContext returns an iterator over objects of the bucket which keys start with the prefix.
+// Keys containing the delimiter after the prefix are rolled up into common prefixes when the delimiter is set.
+func (c *bucketClient) ListWithContext(ctx context.Context, prefix, delimiter string) *ObjectIterator {
+	return c.client.ListWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    prefix,
+		},
+		delimiter,
+	)
+}
+
+// ListVersionsWithContext returns an iterator over all versions and delete markers of objects
+// of the bucket which keys start with the prefix.
+func (c *bucketClient) ListVersionsWithContext(ctx context.Context, prefix string) *ObjectIterator {
+	return c.client.ListVersionsWithContext(
+		ctx,
+		S3Path{
+			Bucket: c.bucket,
+			Key:    prefix,
+		},
+	)
+}
+
+// ListIncompleteUploadsWithContext returns all incomplete multipart uploads of the bucket
+// which keys start with the prefix.
+func (c *bucketClient) ListIncompleteUploadsWithContext(ctx context.Context, prefix string) ([]IncompleteUpload, error) {
+	return c.client.ListIncompleteUploadsWithContext(ctx, c.bucket, prefix)
+}
+
+// AbortStaleUploadsWithContext aborts all incomplete multipart uploads of the bucket initiated
+// more than olderThan ago and returns a number of uploads aborted.
+func (c *bucketClient) AbortStaleUploadsWithContext(ctx context.Context, olderThan time.Duration) (int, error) {
+	return c.client.AbortStaleUploadsWithContext(ctx, c.bucket, olderThan)
+}
+
+// BucketExistsWithContext returns true if the bucket exists and is accessible by the client.
+func (c *bucketClient) BucketExistsWithContext(ctx context.Context) (bool, error) {
+	return c.client.BucketExistsWithContext(ctx, c.bucket)
+}
+
+// DeleteBucketWithContext deletes the bucket, force deletes all its objects first.
+func (c *bucketClient) DeleteBucketWithContext(ctx context.Context, force bool) error {
+	return c.client.DeleteBucketWithContext(ctx, c.bucket, force)
+}
+
+// GetLifecycleRulesWithContext returns lifecycle rules of the bucket.
+func (c *bucketClient) GetLifecycleRulesWithContext(ctx context.Context) ([]LifecycleRule, error) {
+	return c.client.GetLifecycleRulesWithContext(ctx, c.bucket)
+}
+
+// SetLifecycleRulesWithContext replaces lifecycle rules of the bucket, empty rules delete them.
+func (c *bucketClient) SetLifecycleRulesWithContext(ctx context.Context, rules []LifecycleRule) error {
+	return c.client.SetLifecycleRulesWithContext(ctx, c.bucket, rules)
+}
+
+// GetBucketPolicyWithContext returns a JSON policy of the bucket or an empty string if none is set.
+func (c *bucketClient) GetBucketPolicyWithContext(ctx context.Context) (string, error) {
+	return c.client.GetBucketPolicyWithContext(ctx, c.bucket)
+}
+
+// SetBucketPolicyWithContext replaces a JSON policy of the bucket, an empty policy deletes it.
+func (c *bucketClient) SetBucketPolicyWithContext(ctx context.Context, policy string) error {
+	return c.client.SetBucketPolicyWithContext(ctx, c.bucket, policy)
+}
@@ -1,14 +1,103 @@
 package s3client
 
-import "time"
+import (
+	"context"
+	"io"
+	"time"
+)
 
 type BucketClient interface {
+	BucketClientCtx
+
+	Bucket() string
+	Client() S3Client
+
 	Exists(key string) (bool, error)
 	GetSize(key string, callerPays bool) (int64, error)
+	Stat(key string, callerPays bool) (ObjectInfo, error)
+	GetTags(key string) (map[string]string, error)
+	SetTags(key string, tags map[string]string) error
 	GetObject(key string, callerPays bool) ([]byte, error)
+	GetObjectStream(key string, callerPays bool, byteRange *ByteRange) (io.ReadCloser, error)
+	DownloadFile(key, localPath string, opts DownloadFileOptions) error
+	GetObjectIfChanged(key string, callerPays bool, cond GetConditions) ([]byte, ObjectInfo, error)
+	PutObject(key string, body io.Reader) error
+	Upload(key string, body io.Reader, opts UploadOptions) error
+	UploadFile(localPath, key string, opts UploadFileOptions) error
 	Delete(key string) error
+	DeleteMany(keys []string) ([]DeleteFailure, error)
+	DeletePrefix(prefix string) (int, error)
 	Copy(src, dst string, validateEtag, callerPays bool) error
+	CopyObject(src, dst string, opts CopyOptions) error
+	CopyTo(dstBucket BucketClient, srcKey, dstKey string) error
+	CopyObjectTo(dstBucket BucketClient, srcKey, dstKey string, opts CopyOptions) error
 	IsSrcNewer(src, dst string, callerPays bool) (bool, error)
+	Sync(srcPrefix, dstPrefix string, opts SyncOptions) (*SyncReport, error)
+	SyncFromDir(localDir, dstPrefix string, opts SyncOptions) (*SyncReport, error)
 	GetPresignedURL(key string, duration time.Duration) (string, error)
+	GetPresignedPutURL(key string, duration time.Duration, contentType, contentMD5 string) (string, error)
+	GetPresignedDeleteURL(key string, duration time.Duration) (string, error)
+	GetPresignedPost(key string, duration time.Duration, opts PostPolicyOptions) (*PresignedPost, error)
 	GetETag(key string) (string, error)
+	List(prefix, delimiter string) *ObjectIterator
+	ListVersions(prefix string) *ObjectIterator
+	ListIncompleteUploads(prefix string) ([]IncompleteUpload, error)
+	AbortStaleUploads(olderThan time.Duration) (int, error)
+	BucketExists() (bool, error)
+	DeleteBucket(force bool) error
+	GetLifecycleRules() ([]LifecycleRule, error)
+	SetLifecycleRules(rules []LifecycleRule) error
+	GetBucketPolicy() (string, error)
+	SetBucketPolicy(policy string) error
+}
+
+// BucketClientCtx is a bucket's client which methods accept a context to cancel a call.
+type BucketClientCtx interface {
+	ExistsWithContext(ctx context.Context, key string) (bool, error)
+	GetSizeWithContext(ctx context.Context, key string, callerPays bool) (int64, error)
+	StatWithContext(ctx context.Context, key string, callerPays bool) (ObjectInfo, error)
+	GetTagsWithContext(ctx context.Context, key string) (map[string]string, error)
+	SetTagsWithContext(ctx context.Context, key string, tags map[string]string) error
+	GetObjectWithContext(ctx context.Context, key string, callerPays bool) ([]byte, error)
+	GetObjectStreamWithContext(ctx context.Context, key string, callerPays bool, byteRange *ByteRange) (io.ReadCloser, error)
+	DownloadFileWithContext(ctx context.Context, key, localPath string, opts DownloadFileOptions) error
+	GetObjectIfChangedWithContext(ctx context.Context, key string, callerPays bool, cond GetConditions) ([]byte, ObjectInfo, error)
+	PutObjectWithContext(ctx context.Context, key string, body io.Reader) error
+	UploadWithContext(ctx context.Context, key string, body io.Reader, opts UploadOptions) error
+	UploadFileWithContext(ctx context.Context, localPath, key string, opts UploadFileOptions) error
+	DeleteWithContext(ctx context.Context, key string) error
+	DeleteManyWithContext(ctx context.Context, keys []string) ([]DeleteFailure, error)
+	DeletePrefixWithContext(ctx context.Context, prefix string) (int, error)
+	CopyWithContext(ctx context.Context, src, dst string, validateEtag, callerPays bool) error
+	CopyObjectWithContext(ctx context.Context, src, dst string, opts CopyOptions) error
+	CopyToWithContext(ctx context.Context, dstBucket BucketClient, srcKey, dstKey string) error
+	CopyObjectToWithContext(ctx context.Context, dstBucket BucketClient, srcKey, dstKey string, opts CopyOptions) error
+	IsSrcNewerWithContext(ctx context.Context, src, dst string, callerPays bool) (bool, error)
+	SyncWithContext(ctx context.Context, srcPrefix, dstPrefix string, opts SyncOptions) (*SyncReport, error)
+	SyncFromDirWithContext(ctx context.Context, localDir, dstPrefix string, opts SyncOptions) (*SyncReport, error)
+	GetPresignedURLWithContext(ctx context.Context, key string, duration time.Duration) (string, error)
+	GetPresignedPutURLWithContext(
+		ctx context.Context,
+		key string,
+		duration time.Duration,
+		contentType, contentMD5 string,
+	) (string, error)
+	GetPresignedDeleteURLWithContext(ctx context.Context, key string, duration time.Duration) (string, error)
+	GetPresignedPostWithContext(
+		ctx context.Context,
+		key string,
+		duration time.Duration,
+		opts PostPolicyOptions,
+	) (*PresignedPost, error)
+	GetETagWithContext(ctx context.Context, key string) (string, error)
+	ListWithContext(ctx context.Context, prefix, delimiter string) *ObjectIterator
+	ListVersionsWithContext(ctx context.Context, prefix string) *ObjectIterator
+	ListIncompleteUploadsWithContext(ctx context.Context, prefix string) ([]IncompleteUpload, error)
+	AbortStaleUploadsWithContext(ctx context.Context, olderThan time.Duration) (int, error)
+	BucketExistsWithContext(ctx context.Context) (bool, error)
+	DeleteBucketWithContext(ctx context.Context, force bool) error
+	GetLifecycleRulesWithContext(ctx context.Context) ([]LifecycleRule, error)
+	SetLifecycleRulesWithContext(ctx context.Context, rules []LifecycleRule) error
+	GetBucketPolicyWithContext(ctx context.Context) (string, error)
+	SetBucketPolicyWithContext(ctx context.Context, policy string) error
 }
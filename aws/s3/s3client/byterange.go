@@ -0,0 +1,63 @@
+package s3client
+
+import "fmt"
+
+// ByteRange is a range of bytes of an S3 object.
+// Start and End are zero-based and inclusive. A negative End means "up to the end of the object".
+// A negative Start means "the last -Start bytes of the object", End is ignored in this case.
+type ByteRange struct {
+	Start int64
+	End   int64
+}
+
+// NewByteRange returns a range of bytes [start, end].
+func NewByteRange(start, end int64) *ByteRange {
+	return &ByteRange{
+		Start: start,
+		End:   end,
+	}
+}
+
+// NewSuffixByteRange returns a range of the last length bytes of an object.
+func NewSuffixByteRange(length int64) *ByteRange {
+	return &ByteRange{
+		Start: -length,
+		End:   -1,
+	}
+}
+
+// String returns a value of the HTTP Range header for the range.
+func (r *ByteRange) String() string {
+	if r.Start < 0 {
+		return fmt.Sprintf("bytes=%v", r.Start)
+	}
+
+	if r.End < 0 {
+		return fmt.Sprintf("bytes=%v-", r.Start)
+	}
+
+	return fmt.Sprintf("bytes=%v-%v", r.Start, r.End)
+}
+
+// bounds returns inclusive offsets of the range within an object of the size.
+// It returns false if the range is not satisfiable.
+func (r *ByteRange) bounds(size int64) (int64, int64, bool) {
+	start, end := r.Start, r.End
+	if start < 0 {
+		start += size
+		if start < 0 {
+			start = 0
+		}
+		end = size - 1
+	}
+
+	if end < 0 || end >= size {
+		end = size - 1
+	}
+
+	if start >= size || start > end {
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
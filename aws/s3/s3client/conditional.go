@@ -0,0 +1,84 @@
+package s3client
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	headerIfNoneMatch = "If-None-Match"
+	ifNoneMatchAny    = "*"
+)
+
+// GetConditions contains conditions of a conditional GET. An object is returned only if all the conditions
+// set are met, ErrNotModified is returned otherwise.
+type GetConditions struct {
+	// IfNoneMatch is an ETag of a cached object copy, the object is returned if its ETag differs.
+	IfNoneMatch string
+	// IfModifiedSince is a time of a cached object copy, the object is returned if it was modified after it.
+	IfModifiedSince time.Time
+}
+
+// GetObjectIfChangedWithContext returns an S3 object content and attributes if the conditions are met
+// or ErrNotModified if the object has not been changed.
+func (c *s3Client) GetObjectIfChangedWithContext(
+	ctx context.Context,
+	path S3Path,
+	callerPays bool,
+	cond GetConditions,
+) ([]byte, ObjectInfo, error) {
+	params := &s3.GetObjectInput{
+		Bucket:              aws.String(path.Bucket),
+		Key:                 aws.String(path.Key),
+		VersionId:           path.versionID(),
+		RequestPayer:        c.opts.requestPayer(callerPays),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}
+	if cond.IfNoneMatch != "" {
+		params.IfNoneMatch = aws.String(cond.IfNoneMatch)
+	}
+	if !cond.IfModifiedSince.IsZero() {
+		params.IfModifiedSince = aws.Time(cond.IfModifiedSince)
+	}
+
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = c.opts.encryption.customer()
+
+	resp, err := c.awsS3.GetObjectWithContext(ctx, params)
+	if err != nil {
+		return nil, ObjectInfo{}, newError(path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, ObjectInfo{}, newError(path, err)
+	}
+
+	return data, newObjectInfoFromGet(path, resp), nil
+}
+
+// ifAbsentOption is a request option making object writes conditional, so an object is created only
+// if it does not exist. Only the requests completing a write accept the condition.
+func ifAbsentOption(req *request.Request) {
+	switch req.Operation.Name {
+	case "PutObject", "CompleteMultipartUpload":
+		req.HTTPRequest.Header.Set(headerIfNoneMatch, ifNoneMatchAny)
+	}
+}
+
+// isNotModified returns true if the object attributes do not meet the conditions.
+func (cond GetConditions) isNotModified(eTag string, lastModified time.Time) bool {
+	if cond.IfNoneMatch != "" {
+		// If-None-Match takes precedence over If-Modified-Since as HTTP defines.
+		return cond.IfNoneMatch == eTag || cond.IfNoneMatch == ifNoneMatchAny
+	}
+
+	// HTTP dates have a second precision.
+	return !cond.IfModifiedSince.IsZero() &&
+		!lastModified.Truncate(time.Second).After(cond.IfModifiedSince.Truncate(time.Second))
+}
@@ -0,0 +1,144 @@
+package s3client
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// DefaultMaxRetries is a number of retries of a failed request by default.
+	DefaultMaxRetries = client.DefaultRetryerMaxNumRetries
+	// DefaultEndpointRegion is a region used with a custom endpoint when none is set,
+	// S3 compatible stores like MinIO accept it by default.
+	DefaultEndpointRegion = "us-east-1"
+)
+
+// Config contains parameters of an S3 connection. Zero values leave the AWS SDK defaults,
+// so an empty config connects to AWS using the default credentials chain and the environment region.
+type Config struct {
+	// Region is an AWS region, the profile or the environment one is used when empty.
+	Region string
+	// Endpoint is a custom endpoint URL of an S3 compatible store, e.g. http://localhost:9000 for MinIO.
+	Endpoint string
+	// ForcePathStyle addresses buckets in a URL path instead of a host name, S3 compatible stores usually need it.
+	ForcePathStyle bool
+
+	// Profile is a name of a shared credentials and config files profile, the default one is used when empty.
+	Profile string
+	// AccessKeyID and SecretAccessKey set static credentials used instead of the profile ones.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// MaxRetries is a max number of retries of a failed request.
+	// DefaultMaxRetries is used when 0, a negative value disables retries.
+	MaxRetries int
+	// MinRetryDelay and MaxRetryDelay limit an exponential delay between retries, SDK defaults are used when 0.
+	MinRetryDelay time.Duration
+	MaxRetryDelay time.Duration
+	// Timeout limits the time of connecting to S3 and of waiting for a response headers, there is no limit when 0.
+	// A response body transfer is not limited, so large objects are streamed as long as the data flows,
+	// a context deadline limits the whole call.
+	Timeout time.Duration
+}
+
+// timeoutTransport returns the default HTTP transport which dial, TLS handshake and response headers
+// waiting are limited by the timeout.
+func timeoutTransport(timeout time.Duration) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = timeout
+	transport.ResponseHeaderTimeout = timeout
+
+	return transport
+}
+
+// NewSession returns an AWS session configured by the config.
+func NewSession(cfg Config) (*session.Session, error) {
+	awsCfg := aws.NewConfig()
+
+	region := cfg.Region
+	if region == "" && cfg.Endpoint != "" {
+		region = DefaultEndpointRegion
+	}
+	if region != "" {
+		awsCfg = awsCfg.WithRegion(region)
+	}
+
+	if cfg.Endpoint != "" {
+		awsCfg = awsCfg.WithEndpoint(cfg.Endpoint)
+	}
+
+	if cfg.ForcePathStyle {
+		awsCfg = awsCfg.WithS3ForcePathStyle(true)
+	}
+
+	if cfg.AccessKeyID != "" {
+		awsCfg = awsCfg.WithCredentials(
+			credentials.NewStaticCredentials(cfg.AccessKeyID, cfg.SecretAccessKey, cfg.SessionToken),
+		)
+	}
+
+	if cfg.Timeout > 0 {
+		awsCfg = awsCfg.WithHTTPClient(&http.Client{
+			Transport: timeoutTransport(cfg.Timeout),
+		})
+	}
+
+	maxRetries := cfg.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	} else if maxRetries < 0 {
+		maxRetries = 0
+	}
+	awsCfg = request.WithRetryer(awsCfg, client.DefaultRetryer{
+		NumMaxRetries:    maxRetries,
+		MinRetryDelay:    cfg.MinRetryDelay,
+		MinThrottleDelay: cfg.MinRetryDelay,
+		MaxRetryDelay:    cfg.MaxRetryDelay,
+		MaxThrottleDelay: cfg.MaxRetryDelay,
+	})
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsCfg,
+		Profile:           cfg.Profile,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating AWS session: %w", err)
+	}
+
+	return sess, nil
+}
+
+// NewClient returns an S3Client connected by the config.
+func NewClient(cfg Config, opts ...ClientOption) (S3Client, error) {
+	sess, err := NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientFromS3(s3.New(sess), opts...), nil
+}
+
+// NewBucketClientFromConfig returns a bucket's client connected by the config.
+func NewBucketClientFromConfig(cfg Config, bucket string, opts ...ClientOption) (BucketClient, error) {
+	client, err := NewClient(cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBucketClientWithClient(client, bucket), nil
+}
@@ -0,0 +1,247 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// partCopier is implemented by clients able to copy an object by a single request or by a multipart copy.
+type partCopier interface {
+	sourceQuerier
+	copySinglePart(ctx context.Context, src, dst S3Path, eTag string, opts CopyOptions) error
+	createCopyUpload(ctx context.Context, dst S3Path, srcInfo ObjectInfo, opts CopyOptions) (string, error)
+	uploadPartCopy(
+		ctx context.Context,
+		src, dst S3Path,
+		uploadID string,
+		partNumber int64,
+		byteRange *ByteRange,
+		eTag string,
+		opts CopyOptions,
+	) (string, error)
+	completeCopyUpload(ctx context.Context, dst S3Path, uploadID string, parts []uploadedPart, opts CopyOptions) (string, error)
+	abortFailedUpload(path S3Path, uploadID string, err error) error
+}
+
+// copyObject copies source to destination using the client provided and its encryption by default.
+func copyObject(ctx context.Context, c partCopier, src, dst S3Path, opts CopyOptions, encryption *Encryption) error {
+	if err := opts.validate(); err != nil {
+		return fmt.Errorf("error copying %v: %w", src, err)
+	}
+	opts = opts.withDefaults(encryption)
+
+	// a single HEAD provides both the size and the ETag of the source.
+	srcInfo, err := opts.sourceStat(ctx, c, src)
+	if err != nil {
+		return err
+	}
+
+	// a composite ETag of a multipart source is reproduced only by a copy with the same chunk layout.
+	if opts.ValidateETag && (srcInfo.Size > awsSinglePartCopyLimit || multipartETagParts(srcInfo.ETag) > 0) {
+		return copyMultipartValidated(ctx, c, src, dst, srcInfo, opts)
+	}
+
+	if srcInfo.Size > awsSinglePartCopyLimit {
+		_, err = copyMultipart(ctx, c, src, dst, srcInfo, opts)
+
+		return err
+	}
+
+	return c.copySinglePart(ctx, src, dst, srcInfo.ETag, opts)
+}
+
+// copyMultipartValidated copies a large object reproducing the chunk layout of the source,
+// so the composite ETag of the destination can be compared with the source one.
+func copyMultipartValidated(ctx context.Context, c partCopier, src, dst S3Path, srcInfo ObjectInfo, opts CopyOptions) error {
+	srcSize, eTag := srcInfo.Size, srcInfo.ETag
+	srcParts := multipartETagParts(eTag)
+	if srcParts == 0 {
+		// a single part object is copied by a single request that keeps its ETag.
+		if srcSize > awsSinglePartCopyMaxSize {
+			return fmt.Errorf("file size %v with a single part ETag %v cannot be verified using ETags", srcSize, eTag)
+		}
+
+		return c.copySinglePart(ctx, src, dst, eTag, opts)
+	}
+
+	partSize, err := opts.sourcePartSize(ctx, c, src)
+	if err != nil {
+		return err
+	}
+
+	if partSize <= 0 || partsNumber(srcSize, partSize) != int64(srcParts) {
+		return fmt.Errorf("chunk layout of %v with ETag %v cannot be reproduced to verify a copy", src, eTag)
+	}
+
+	opts.ChunkSize = partSize
+	resultETag, err := copyMultipart(ctx, c, src, dst, srcInfo, opts)
+	if err != nil {
+		return err
+	}
+
+	if resultETag != eTag {
+		return newETagMismatchError(dst, resultETag, eTag)
+	}
+
+	return nil
+}
+
+// copyMultipart copies source to destination by a multipart copy and returns a composite ETag
+// computed from the parts copied. The ETag is validated against the result one if required.
+// A failed copy is aborted to not leave its parts behind.
+func copyMultipart(ctx context.Context, c partCopier, src, dst S3Path, srcInfo ObjectInfo, opts CopyOptions) (string, error) {
+	chunkSize, err := multipartChunkSize(srcInfo.Size, opts.ChunkSize)
+	if err != nil {
+		return "", fmt.Errorf("error copying %v: %w", src, err)
+	}
+
+	uploadID, err := c.createCopyUpload(ctx, dst, srcInfo, opts)
+	if err != nil {
+		return "", err
+	}
+
+	parts, err := copyParts(ctx, c, src, dst, uploadID, srcInfo, chunkSize, opts)
+	if err != nil {
+		return "", c.abortFailedUpload(dst, uploadID, err)
+	}
+
+	partETags := make([]string, len(parts))
+	for i, part := range parts {
+		partETags[i] = part.ETag
+	}
+
+	eTag, err := compositeETag(partETags)
+	if err != nil && opts.ValidateETag {
+		return "", c.abortFailedUpload(dst, uploadID, err)
+	}
+
+	resultETag, err := c.completeCopyUpload(ctx, dst, uploadID, parts, opts)
+	if err != nil {
+		return "", c.abortFailedUpload(dst, uploadID, err)
+	}
+
+	if opts.ValidateETag && eTag != resultETag {
+		return "", &Error{
+			Path: dst,
+			Err:  fmt.Errorf("multipart copy result ETag %+v and the one computed from parts is %+v", resultETag, eTag),
+			kind: ErrETagMismatch,
+		}
+	}
+
+	return eTag, nil
+}
+
+// copyParts copies byte ranges of the source as parts of the upload in parallel
+// and returns the parts copied sorted by their numbers.
+func copyParts(
+	ctx context.Context,
+	c partCopier,
+	src, dst S3Path,
+	uploadID string,
+	srcInfo ObjectInfo,
+	chunkSize int64,
+	opts CopyOptions,
+) ([]uploadedPart, error) {
+	// the first failed part cancels the rest of parts being copied.
+	partsCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errCh := make(chan error, partsNumber(srcInfo.Size, chunkSize))
+	defer close(errCh)
+
+	// limits the number of parts copied in parallel.
+	semaphore := make(chan struct{}, opts.Concurrency)
+
+	// guards the parts copied.
+	var mu sync.Mutex
+	parts := []uploadedPart{}
+
+	var wg sync.WaitGroup
+	for i, start := int64(1), int64(0); start < srcInfo.Size && partsCtx.Err() == nil; i, start = i+1, start+chunkSize {
+		end := start + chunkSize - 1
+		if end >= srcInfo.Size {
+			end = srcInfo.Size - 1
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-partsCtx.Done():
+			continue
+		}
+
+		wg.Add(1)
+		go func(partNumber, start, end int64) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			var eTag string
+			err := withRetries(partsCtx, opts.MaxRetries, opts.RetryDelay, func() error {
+				var partErr error
+				// all the parts must be copied from the same source version the HEAD returned.
+				eTag, partErr = c.uploadPartCopy(partsCtx, src, dst, uploadID, partNumber,
+					NewByteRange(start, end), srcInfo.ETag, opts)
+
+				return partErr
+			})
+			if err != nil {
+				errCh <- fmt.Errorf("failed to copy part %v: %w", partNumber, err)
+				cancel()
+
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			parts = append(parts, uploadedPart{PartNumber: partNumber, ETag: eTag})
+		}(i, start, end)
+	}
+
+	// wait until all parts are copied.
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("multipart copy cancelled: %w", ctx.Err())
+	}
+
+	if len(errCh) > 0 {
+		// the first error is wrapped, so its kind can be checked by errors.Is.
+		partsErr := fmt.Errorf("multipart upload error(s): [%w]", <-errCh)
+		for len(errCh) > 0 {
+			partsErr = fmt.Errorf("%w [%v]", partsErr, <-errCh)
+		}
+
+		return nil, partsErr
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+
+	return parts, nil
+}
+
+// copyErrorPath returns a path of a failed CopyObject request the err is reported for: the source one
+// if the source object is missing, has changed since its HEAD or is archived, the destination one otherwise.
+func copyErrorPath(src, dst S3Path, err error) S3Path {
+	switch errorCode(err) {
+	case awsErrNoSuchKey, awsErrNoSuchVersion, awsErrPreconditionFailed, awsErrInvalidObjectState:
+		return src
+	}
+
+	return dst
+}
+
+// partCopyErrorPath returns a path of a failed UploadPartCopy request the err is reported for.
+// The destination is only written by the upload completion, so it is the source one unless the upload is missing.
+func partCopyErrorPath(src, dst S3Path, err error) S3Path {
+	if errorCode(err) == awsErrNoSuchUpload {
+		return dst
+	}
+
+	return src
+}
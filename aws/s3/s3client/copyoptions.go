@@ -0,0 +1,199 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	// DefaultCopyConcurrency is a number of parts of a multipart copy copied in parallel by default.
+	DefaultCopyConcurrency = 10
+	// DefaultPartMaxRetries is a number of retries of a failed multipart copy part by default.
+	DefaultPartMaxRetries = 3
+	// DefaultPartRetryDelay is a delay before the first retry of a failed part, doubled on every next retry.
+	DefaultPartRetryDelay = 200 * time.Millisecond
+
+	awsErrSlowDown = "SlowDown" // S3 throttling error code.
+
+	// maxUploadParts is the max number of parts of a multipart upload S3 accepts.
+	maxUploadParts = 10000
+	// minPartSize and maxPartSize are S3 limits of a multipart upload part size, the last part may be smaller.
+	minPartSize int64 = 1024 * 1024 * 5
+	maxPartSize int64 = 1024 * 1024 * 1024 * 5
+)
+
+// CopyOptions contains parameters of a copy operation.
+type CopyOptions struct {
+	// ValidateETag enables the result integrity check by comparing an ETag of source and destination.
+	ValidateETag bool
+	// CallerPays is set when the requester pays for the source object access.
+	CallerPays bool
+	// Concurrency is a max number of parts of a multipart copy copied in parallel.
+	// DefaultCopyConcurrency is used when 0.
+	Concurrency int
+	// ChunkSize is a size of a part of a multipart copy. DefaultMultipartChunkSize is used when 0.
+	// It must be within S3 part size limits of 5Mb-5Gb and is raised if an object does not fit into 10000 parts.
+	ChunkSize int64
+	// MaxRetries is a max number of retries of a part failed with a throttling or server error.
+	// DefaultPartMaxRetries is used when 0, a negative value disables retries.
+	MaxRetries int
+	// RetryDelay is a delay before the first retry of a failed part, doubled on every next retry.
+	// DefaultPartRetryDelay is used when 0.
+	RetryDelay time.Duration
+	// ReplaceMetadata sets Metadata and ContentType to the destination instead of the source ones.
+	// The source metadata and content type are preserved otherwise.
+	ReplaceMetadata bool
+	Metadata        map[string]string
+	ContentType     string
+	// Encryption is a server-side encryption of the destination. The client one is used when nil.
+	// ETags of SSE-KMS and SSE-C encrypted objects are not MD5 hashes, so they cannot be validated.
+	Encryption *Encryption
+	// SourceEncryption provides an SSE-C key of the source. The client one is used when nil.
+	// A SourceClient not created by this package cannot accept the key, so the copy fails in this case.
+	SourceEncryption *Encryption
+	// SourceClient is a client of the source bucket its attributes are queried by. It must be set when
+	// the source bucket is in another region than the client one. The copy itself is still made by the client,
+	// so its credentials must allow reading the source.
+	SourceClient S3ClientCtx
+}
+
+// sourceQuerier is implemented by clients able to query attributes a copy of an object needs.
+type sourceQuerier interface {
+	stat(ctx context.Context, path S3Path, callerPays bool, encryption *Encryption) (ObjectInfo, error)
+	multipartPartSize(ctx context.Context, path S3Path, callerPays bool, encryption *Encryption) (int64, error)
+}
+
+// sourceStat returns attributes of the copy source by the source client if set or by the client c.
+func (o CopyOptions) sourceStat(ctx context.Context, c sourceQuerier, src S3Path) (ObjectInfo, error) {
+	switch source := o.SourceClient.(type) {
+	case nil:
+		return c.stat(ctx, src, o.CallerPays, o.SourceEncryption)
+	case sourceQuerier:
+		return source.stat(ctx, src, o.CallerPays, o.SourceEncryption)
+	default:
+		return source.StatWithContext(ctx, src, o.CallerPays)
+	}
+}
+
+// sourcePartSize returns a size of the first part of the multipart copy source by the source client
+// if it can query it or by the client c.
+func (o CopyOptions) sourcePartSize(ctx context.Context, c sourceQuerier, src S3Path) (int64, error) {
+	if source, ok := o.SourceClient.(sourceQuerier); ok {
+		c = source
+	}
+
+	return c.multipartPartSize(ctx, src, o.CallerPays, o.SourceEncryption)
+}
+
+// validate returns an error if the source client cannot query the source with its SSE-C key.
+func (o CopyOptions) validate() error {
+	if _, ok := o.SourceClient.(sourceQuerier); ok || o.SourceClient == nil {
+		return nil
+	}
+
+	if o.SourceEncryption != nil && o.SourceEncryption.Type == EncryptionCustomer {
+		return fmt.Errorf("source client %T cannot accept an SSE-C key of the source", o.SourceClient)
+	}
+
+	return nil
+}
+
+// withDefaults returns options with zero values replaced by defaults and the client encryption.
+func (o CopyOptions) withDefaults(encryption *Encryption) CopyOptions {
+	if o.Encryption == nil {
+		o.Encryption = encryption
+	}
+
+	if o.SourceEncryption == nil {
+		o.SourceEncryption = encryption
+	}
+
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultCopyConcurrency
+	}
+
+	if o.ChunkSize <= 0 {
+		o.ChunkSize = DefaultMultipartChunkSize
+	}
+
+	if o.MaxRetries == 0 {
+		o.MaxRetries = DefaultPartMaxRetries
+	} else if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	}
+
+	if o.RetryDelay <= 0 {
+		o.RetryDelay = DefaultPartRetryDelay
+	}
+
+	return o
+}
+
+// multipartChunkSize returns the chunk size raised if required, so an object of the size is split
+// into no more than maxUploadParts parts. A chunk size out of S3 part size limits is an error.
+func multipartChunkSize(size, chunkSize int64) (int64, error) {
+	if partsNumber(size, chunkSize) > 1 && (chunkSize < minPartSize || chunkSize > maxPartSize) {
+		return 0, fmt.Errorf("chunk size %v is out of S3 part size limits [%v, %v]", chunkSize, minPartSize, maxPartSize)
+	}
+
+	if partsNumber(size, chunkSize) > maxUploadParts {
+		chunkSize = partsNumber(size, maxUploadParts)
+		if chunkSize > maxPartSize {
+			return 0, fmt.Errorf("size %v exceeds the max size %v of a multipart object", size, maxPartSize*maxUploadParts)
+		}
+	}
+
+	return chunkSize, nil
+}
+
+// withRetries calls the fn until it succeeds, fails with an error that cannot be retried
+// or the number of retries is exceeded. The delay between retries grows exponentially.
+func withRetries(ctx context.Context, maxRetries int, delay time.Duration, fn func() error) error {
+	err := fn()
+	for retry := 0; err != nil && retry < maxRetries && isRetryableError(err); retry++ {
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		err = fn()
+	}
+
+	return err
+}
+
+// isRetryableError returns true if the err is a throttling, server or a temporary network error.
+func isRetryableError(err error) bool {
+	// the SDK v1 classifier treats any error it does not know as retryable, so it gets SDK v1 errors only.
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && (request.IsErrorThrottle(awsErr) || request.IsErrorRetryable(awsErr)) {
+		return true
+	}
+
+	if errorCode(err) == awsErrSlowDown {
+		return true
+	}
+
+	if status := errorStatus(err); status != 0 {
+		return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	// a connection broken while a body is transferred.
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF)
+}
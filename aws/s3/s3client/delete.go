@@ -0,0 +1,215 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// DefaultDeleteConcurrency is a number of batch delete requests sent in parallel by DeletePrefix.
+	DefaultDeleteConcurrency = 5
+
+	awsDeleteObjectsLimit = 1000 // max number of keys in a single DeleteObjects request.
+)
+
+// DeleteFailure describes an object that was not deleted by a batch delete.
+type DeleteFailure struct {
+	Path    S3Path
+	Code    string
+	Message string
+}
+
+// Error implements error interface.
+func (f DeleteFailure) Error() string {
+	return fmt.Sprintf("%v: %v %v", f.Path.FullPath(), f.Code, f.Message)
+}
+
+// deleteBatchFunc deletes up to 1000 objects of the bucket by a single request and returns objects that were not deleted.
+type deleteBatchFunc func(ctx context.Context, bucket string, objs []S3Path) ([]DeleteFailure, error)
+
+// listFunc returns an iterator over objects of the prefix.
+type listFunc func(ctx context.Context, prefix S3Path, delimiter string) *ObjectIterator
+
+// DeleteManyWithContext deletes objects by batches of up to 1000 keys per request
+// and returns objects that were not deleted.
+func (c *s3Client) DeleteManyWithContext(ctx context.Context, objs []S3Path) ([]DeleteFailure, error) {
+	return deleteMany(ctx, objs, c.deleteBatch)
+}
+
+// DeletePrefixWithContext deletes all objects which keys start with the prefix.Key
+// and returns a number of objects deleted. An empty prefix.Key is an error.
+func (c *s3Client) DeletePrefixWithContext(ctx context.Context, prefix S3Path) (int, error) {
+	if err := checkDeletePrefix(prefix); err != nil {
+		return 0, err
+	}
+
+	return deletePrefix(ctx, prefix, c.ListWithContext, c.deleteBatch)
+}
+
+// checkDeletePrefix returns an error for a prefix without a key, so all objects of a bucket
+// are never deleted by mistake. DeleteBucket with force empties a bucket deliberately.
+func checkDeletePrefix(prefix S3Path) error {
+	if prefix.Key == "" {
+		return fmt.Errorf("refusing to delete all objects of bucket %v: %w", prefix.Bucket, ErrPathNoKey)
+	}
+
+	return nil
+}
+
+// deleteMany groups objects by buckets and deletes them by batches using the deleteBatch.
+func deleteMany(ctx context.Context, objs []S3Path, deleteBatch deleteBatchFunc) ([]DeleteFailure, error) {
+	byBucket := map[string][]S3Path{}
+	buckets := []string{}
+	for _, obj := range objs {
+		if _, ok := byBucket[obj.Bucket]; !ok {
+			buckets = append(buckets, obj.Bucket)
+		}
+		byBucket[obj.Bucket] = append(byBucket[obj.Bucket], obj)
+	}
+
+	failures := []DeleteFailure{}
+	for _, bucket := range buckets {
+		bucketObjs := byBucket[bucket]
+		for start := 0; start < len(bucketObjs); start += awsDeleteObjectsLimit {
+			end := start + awsDeleteObjectsLimit
+			if end > len(bucketObjs) {
+				end = len(bucketObjs)
+			}
+
+			batchFailures, err := deleteBatch(ctx, bucket, bucketObjs[start:end])
+			failures = append(failures, batchFailures...)
+			if err != nil {
+				return failures, err
+			}
+		}
+	}
+
+	return failures, nil
+}
+
+// deletePrefix deletes objects of the prefix listed by the list function by concurrent batches.
+func deletePrefix(ctx context.Context, prefix S3Path, list listFunc, deleteBatch deleteBatchFunc) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		deleted  int
+		failures []DeleteFailure
+		firstErr error
+	)
+
+	semaphore := make(chan struct{}, DefaultDeleteConcurrency)
+	deleteKeys := func(keys []S3Path) {
+		defer func() {
+			<-semaphore
+			wg.Done()
+		}()
+
+		batchFailures, err := deleteBatch(ctx, prefix.Bucket, keys)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		deleted += len(keys) - len(batchFailures)
+		failures = append(failures, batchFailures...)
+		if err != nil && firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	it := list(ctx, prefix, "")
+	keys := make([]S3Path, 0, awsDeleteObjectsLimit)
+	for it.Next() {
+		keys = append(keys, it.Object().Path)
+		if len(keys) < awsDeleteObjectsLimit {
+			continue
+		}
+
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go deleteKeys(keys)
+		keys = make([]S3Path, 0, awsDeleteObjectsLimit)
+	}
+
+	if len(keys) > 0 && it.Err() == nil {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go deleteKeys(keys)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return deleted, firstErr
+	}
+
+	if it.Err() != nil {
+		return deleted, it.Err()
+	}
+
+	if len(failures) > 0 {
+		msgs := make([]string, len(failures))
+		for i, f := range failures {
+			msgs[i] = f.Error()
+		}
+
+		return deleted, fmt.Errorf("failed to delete %v object(s): [%v]", len(failures), strings.Join(msgs, "], ["))
+	}
+
+	return deleted, nil
+}
+
+// deleteBatch implements deleteBatchFunc.
+func (c *s3Client) deleteBatch(ctx context.Context, bucket string, objs []S3Path) ([]DeleteFailure, error) {
+	objects := make([]*s3.ObjectIdentifier, len(objs))
+	for i, obj := range objs {
+		objects[i] = &s3.ObjectIdentifier{
+			Key:       aws.String(obj.Key),
+			VersionId: obj.versionID(),
+		}
+	}
+
+	resp, err := c.awsS3.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+		Bucket: aws.String(bucket),
+		Delete: &s3.Delete{
+			Objects: objects,
+			Quiet:   aws.Bool(true),
+		},
+		RequestPayer:        c.opts.requestPayer(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		failures := make([]DeleteFailure, len(objs))
+		for i, obj := range objs {
+			failures[i] = DeleteFailure{
+				Path:    obj,
+				Message: err.Error(),
+			}
+		}
+
+		return failures, fmt.Errorf("error deleting %v objects: %w", len(objs), newError(S3Path{Bucket: bucket}, err))
+	}
+
+	failures := make([]DeleteFailure, 0, len(resp.Errors))
+	for _, e := range resp.Errors {
+		failures = append(failures, DeleteFailure{
+			Path: S3Path{
+				Bucket:    bucket,
+				Key:       aws.StringValue(e.Key),
+				VersionID: aws.StringValue(e.VersionId),
+			},
+			Code:    aws.StringValue(e.Code),
+			Message: aws.StringValue(e.Message),
+		})
+	}
+
+	return failures, nil
+}
@@ -0,0 +1,333 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/FurmanovD/go-kit/filesys/fsops"
+)
+
+const (
+	// DefaultDownloadPartSize is a size of a byte range of an object downloaded by a single request by default.
+	DefaultDownloadPartSize int64 = 1024 * 1024 * 16 // 16Mb
+	// DefaultDownloadConcurrency is a number of byte ranges downloaded in parallel by default.
+	DefaultDownloadConcurrency = 5
+)
+
+// DownloadFileOptions contains parameters of an object download to a local file.
+type DownloadFileOptions struct {
+	// PartSize is a size of a byte range downloaded by a single request. DefaultDownloadPartSize is used when 0.
+	PartSize int64
+	// Concurrency is a number of byte ranges downloaded in parallel. DefaultDownloadConcurrency is used when 0.
+	Concurrency int
+	// CallerPays is set when the requester pays for the object access.
+	CallerPays bool
+	// ValidateETag enables the downloaded file integrity check by comparing its MD5 based ETag with the object one.
+	// ETags of SSE-KMS and SSE-C encrypted objects are not MD5 hashes, so they cannot be validated.
+	ValidateETag bool
+	// MaxRetries is a max number of retries of a byte range failed with a throttling or server error.
+	// DefaultPartMaxRetries is used when 0, a negative value disables retries.
+	MaxRetries int
+	// RetryDelay is a delay before the first retry of a failed byte range, doubled on every next retry.
+	// DefaultPartRetryDelay is used when 0.
+	RetryDelay time.Duration
+}
+
+// rangeGetter is implemented by clients able to get a byte range of an object only if its ETag matches.
+type rangeGetter interface {
+	getObjectRange(ctx context.Context, path S3Path, callerPays bool, byteRange *ByteRange, ifMatch string) (io.ReadCloser, error)
+}
+
+// withDefaults returns options with zero values replaced by defaults.
+func (o DownloadFileOptions) withDefaults() DownloadFileOptions {
+	if o.PartSize <= 0 {
+		o.PartSize = DefaultDownloadPartSize
+	}
+
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultDownloadConcurrency
+	}
+
+	if o.MaxRetries == 0 {
+		o.MaxRetries = DefaultPartMaxRetries
+	} else if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	}
+
+	if o.RetryDelay <= 0 {
+		o.RetryDelay = DefaultPartRetryDelay
+	}
+
+	return o
+}
+
+// DownloadFileWithContext downloads an object to a local file by byte ranges fetched in parallel
+// and written to a temporary file, so the object is never buffered in memory. The temporary file is
+// renamed to the local one once its size and the object ETag are verified, so the local file is either
+// replaced by a complete object or left as is.
+func (c *s3Client) DownloadFileWithContext(ctx context.Context, path S3Path, localPath string, opts DownloadFileOptions) error {
+	return downloadFile(ctx, c, path, localPath, opts)
+}
+
+// downloadFile downloads an object to a local file using the client provided.
+func downloadFile(ctx context.Context, c S3ClientCtx, path S3Path, localPath string, opts DownloadFileOptions) error {
+	opts = opts.withDefaults()
+
+	if fsops.IsDir(localPath) {
+		return fmt.Errorf("%s destination is a directory", localPath)
+	}
+
+	info, err := c.StatWithContext(ctx, path, opts.CallerPays)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(localPath)
+	if err = os.MkdirAll(dir, os.ModeDir|os.ModePerm); err != nil {
+		return fmt.Errorf("error creating a download directory: %w", err)
+	}
+
+	// the temporary file is created in the same directory, so it is renamed atomically.
+	tmp, err := createDownloadFile(localPath)
+	if err != nil {
+		return fmt.Errorf("error creating a download file: %w", err)
+	}
+
+	tmpPath := tmp.Name()
+	renamed := false
+	defer func() {
+		tmp.Close()
+		if !renamed {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if err = tmp.Truncate(info.Size); err != nil {
+		return fmt.Errorf("error allocating a download file: %w", err)
+	}
+
+	if err = downloadFileParts(ctx, c, path, tmp, info, opts); err != nil {
+		return err
+	}
+
+	if err = tmp.Sync(); err != nil {
+		return fmt.Errorf("error writing a download file: %w", err)
+	}
+
+	if err = verifyDownload(ctx, c, path, tmpPath, info, opts); err != nil {
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("error writing a download file: %w", err)
+	}
+
+	if err = os.Rename(tmpPath, localPath); err != nil {
+		return fmt.Errorf("error moving a download file: %w", err)
+	}
+	renamed = true
+
+	return nil
+}
+
+// createDownloadFile creates a temporary file in the directory of the local file. Unlike os.CreateTemp,
+// the file gets the mode of the local file it replaces or the default one the umask is applied to.
+func createDownloadFile(localPath string) (*os.File, error) {
+	dir, base := filepath.Split(localPath)
+
+	for try := 0; ; try++ {
+		name := filepath.Join(dir, "."+base+"."+strconv.FormatUint(uint64(rand.Uint32()), 10)+".download")
+
+		file, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+		if os.IsExist(err) && try < 100 {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// the umask is not applied to the mode of a file replaced.
+		if info, statErr := os.Stat(localPath); statErr == nil {
+			if err = file.Chmod(info.Mode().Perm()); err != nil {
+				file.Close()
+				os.Remove(name)
+
+				return nil, err
+			}
+		}
+
+		return file, nil
+	}
+}
+
+// downloadFileParts downloads byte ranges of the object in parallel writing them to the file.
+// Every byte range is requested only if the object ETag still matches the info one.
+func downloadFileParts(ctx context.Context, c S3ClientCtx, path S3Path, file *os.File, info ObjectInfo, opts DownloadFileOptions) error {
+	size := info.Size
+
+	// the first failed part cancels the rest of parts being downloaded.
+	partsCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errCh := make(chan error, partsNumber(size, opts.PartSize))
+	defer close(errCh)
+
+	// limits the number of parts downloaded in parallel.
+	semaphore := make(chan struct{}, opts.Concurrency)
+
+	var wg sync.WaitGroup
+	for start := int64(0); start < size && partsCtx.Err() == nil; start += opts.PartSize {
+		end := start + opts.PartSize - 1
+		if end >= size {
+			end = size - 1
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-partsCtx.Done():
+			continue
+		}
+
+		wg.Add(1)
+		go func(start, end int64) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			// a part is rewritten from its start on every retry.
+			err := withRetries(partsCtx, opts.MaxRetries, opts.RetryDelay, func() error {
+				return downloadFilePart(partsCtx, c, path, info.ETag, io.NewOffsetWriter(file, start), start, end, opts.CallerPays)
+			})
+			if err != nil {
+				errCh <- fmt.Errorf("failed to download bytes %v-%v: %w", start, end, err)
+				cancel()
+			}
+		}(start, end)
+	}
+
+	// wait until all parts are downloaded.
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("file download cancelled: %w", ctx.Err())
+	}
+
+	if len(errCh) > 0 {
+		// the first error is wrapped, so its kind can be checked by errors.Is.
+		partsErr := fmt.Errorf("download error(s): [%w]", <-errCh)
+		for len(errCh) > 0 {
+			partsErr = fmt.Errorf("%w [%v]", partsErr, <-errCh)
+		}
+
+		return partsErr
+	}
+
+	return nil
+}
+
+// downloadFilePart downloads the byte range [start, end] of the object to the writer.
+// The range is requested only if the object ETag matches the eTag if the client supports it.
+func downloadFilePart(ctx context.Context, c S3ClientCtx, path S3Path, eTag string, w io.Writer, start, end int64, callerPays bool) error {
+	var body io.ReadCloser
+	var err error
+	if getter, ok := c.(rangeGetter); ok {
+		body, err = getter.getObjectRange(ctx, path, callerPays, NewByteRange(start, end), eTag)
+	} else {
+		body, err = c.GetObjectStreamWithContext(ctx, path, callerPays, NewByteRange(start, end))
+	}
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return err
+	}
+
+	if n != end-start+1 {
+		return fmt.Errorf("got %v bytes of %v: %w", n, end-start+1, io.ErrUnexpectedEOF)
+	}
+
+	return nil
+}
+
+// verifyDownload checks the downloaded file matches the object attributes the download started with.
+// The object is queried once again as its byte ranges are downloaded by separate requests,
+// so an object replaced during the download leaves a file assembled from different objects.
+func verifyDownload(ctx context.Context, c S3ClientCtx, path S3Path, localPath string, info ObjectInfo, opts DownloadFileOptions) error {
+	fileInfo, err := os.Stat(localPath)
+	if err != nil {
+		return fmt.Errorf("error querying a download file: %w", err)
+	}
+
+	if fileInfo.Size() != info.Size {
+		return fmt.Errorf("downloaded %v bytes of %v object %v", fileInfo.Size(), info.Size, path)
+	}
+
+	current, err := c.StatWithContext(ctx, path, opts.CallerPays)
+	if err != nil {
+		return err
+	}
+
+	if current.ETag != info.ETag {
+		return &Error{
+			Path: path,
+			Err:  fmt.Errorf("object ETag changed from %+v to %+v during download", info.ETag, current.ETag),
+			kind: ErrETagMismatch,
+		}
+	}
+
+	if !opts.ValidateETag {
+		return nil
+	}
+
+	eTag, err := downloadedETag(ctx, c, path, localPath, info, opts.CallerPays)
+	if err != nil {
+		return fmt.Errorf("error computing a download file ETag: %w", err)
+	}
+
+	if eTag != info.ETag {
+		return &Error{
+			Path: path,
+			Err:  fmt.Errorf("downloaded file ETag %+v and the object one is %+v", eTag, info.ETag),
+			kind: ErrETagMismatch,
+		}
+	}
+
+	return nil
+}
+
+// downloadedETag computes an ETag of the downloaded file using the part size of the object.
+func downloadedETag(ctx context.Context, c S3ClientCtx, path S3Path, localPath string, info ObjectInfo, callerPays bool) (string, error) {
+	parts := multipartETagParts(info.ETag)
+	if parts == 0 {
+		return fileETag(localPath, info.Size)
+	}
+
+	querier, ok := c.(sourceQuerier)
+	if !ok {
+		return "", fmt.Errorf("the client cannot query a part size of multipart object %v", path)
+	}
+
+	partSize, err := querier.multipartPartSize(ctx, path, callerPays, nil)
+	if err != nil {
+		return "", err
+	}
+
+	eTag, err := fileETag(localPath, partSize)
+	if err != nil || parts > 1 {
+		return eTag, err
+	}
+
+	// a single part of a multipart object gets a composite ETag as well.
+	return compositeETag([]string{eTag})
+}
@@ -0,0 +1,91 @@
+package s3client
+
+import (
+	"crypto/md5" //nolint:gosec // MD5 of an SSE-C key is required by S3.
+	"encoding/base64"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// EncryptionType is a type of S3 server-side encryption.
+type EncryptionType string
+
+const (
+	// EncryptionNone uses the bucket default encryption.
+	EncryptionNone EncryptionType = ""
+	// EncryptionS3 is an encryption by S3 managed keys (SSE-S3).
+	EncryptionS3 EncryptionType = s3.ServerSideEncryptionAes256
+	// EncryptionKMS is an encryption by AWS KMS keys (SSE-KMS).
+	EncryptionKMS EncryptionType = s3.ServerSideEncryptionAwsKms
+	// EncryptionCustomer is an encryption by a customer provided key (SSE-C).
+	EncryptionCustomer EncryptionType = "SSE-C"
+
+	sseCustomerAlgorithm = s3.ServerSideEncryptionAes256
+)
+
+// Encryption describes a server-side encryption of S3 objects.
+type Encryption struct {
+	Type EncryptionType
+	// KMSKeyID is an ID of a KMS key for EncryptionKMS, the AWS managed key is used when empty.
+	KMSKeyID string
+	// CustomerKey is a 256-bit key for EncryptionCustomer.
+	CustomerKey []byte
+}
+
+// NewEncryptionS3 returns an SSE-S3 encryption.
+func NewEncryptionS3() *Encryption {
+	return &Encryption{
+		Type: EncryptionS3,
+	}
+}
+
+// NewEncryptionKMS returns an SSE-KMS encryption by the key provided.
+func NewEncryptionKMS(keyID string) *Encryption {
+	return &Encryption{
+		Type:     EncryptionKMS,
+		KMSKeyID: keyID,
+	}
+}
+
+// NewEncryptionCustomer returns an SSE-C encryption by the customer key provided.
+func NewEncryptionCustomer(key []byte) *Encryption {
+	return &Encryption{
+		Type:        EncryptionCustomer,
+		CustomerKey: key,
+	}
+}
+
+// serverSide returns an encryption algorithm and a KMS key ID to store an object with,
+// nil values mean the bucket default encryption.
+func (e *Encryption) serverSide() (sse, kmsKeyID *string) {
+	if e == nil {
+		return nil, nil
+	}
+
+	switch e.Type {
+	case EncryptionS3:
+		return aws.String(string(e.Type)), nil
+	case EncryptionKMS:
+		if e.KMSKeyID != "" {
+			kmsKeyID = aws.String(e.KMSKeyID)
+		}
+
+		return aws.String(string(e.Type)), kmsKeyID
+	default:
+		return nil, nil
+	}
+}
+
+// customer returns an algorithm, a key and its MD5 of an SSE-C encryption, nil values for other types.
+func (e *Encryption) customer() (algorithm, key, keyMD5 *string) {
+	if e == nil || e.Type != EncryptionCustomer {
+		return nil, nil, nil
+	}
+
+	sum := md5.Sum(e.CustomerKey) //nolint:gosec // MD5 of an SSE-C key is required by S3.
+
+	return aws.String(sseCustomerAlgorithm),
+		aws.String(string(e.CustomerKey)),
+		aws.String(base64.StdEncoding.EncodeToString(sum[:]))
+}
@@ -1,8 +1,202 @@
 package s3client
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
 
 var (
 	ErrPathNoBucketSeparator = errors.New("no bucket separator in S3 path")
 	ErrPathNoKey             = errors.New("no key in S3 path")
+	ErrPathInvalidURL        = errors.New("invalid S3 URL")
+	ErrPathInvalidARN        = errors.New("invalid S3 ARN")
+	ErrInvalidBucketName     = errors.New("invalid bucket name")
+
+	// these errors describe a result of an S3 operation and are checked by errors.Is.
+	ErrNotFound           = errors.New("object or bucket not found")
+	ErrAccessDenied       = errors.New("access denied")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrThrottled          = errors.New("request throttled")
+	ErrETagMismatch       = errors.New("ETag mismatch")
+	ErrNotModified        = errors.New("object not modified")
+	// ErrBucketAlreadyExists is returned when a bucket name is taken by another account.
+	ErrBucketAlreadyExists = errors.New("bucket already exists")
+)
+
+const (
+	awsErrNoSuchKey           = "NoSuchKey"
+	awsErrNoSuchBucket        = "NoSuchBucket"
+	awsErrNoSuchUpload        = "NoSuchUpload"
+	awsErrNoSuchVersion       = "NoSuchVersion"
+	awsErrInvalidObjectState  = "InvalidObjectState"
+	awsErrAccessDenied        = "AccessDenied"
+	awsErrPreconditionFailed  = "PreconditionFailed"
+	awsErrConditionalConflict = "ConditionalRequestConflict"
+	awsErrNotModified         = "NotModified"
+	awsErrBucketExists        = "BucketAlreadyExists"
+	awsErrBucketOwned         = "BucketAlreadyOwnedByYou"
+	awsErrBucketNotEmpty      = "BucketNotEmpty"
+	awsErrNoSuchLifecycle     = "NoSuchLifecycleConfiguration"
+	awsErrNoSuchBucketPolicy  = "NoSuchBucketPolicy"
 )
+
+// Error is an error of an S3 operation on the path. It wraps an AWS error, so errors.As
+// to awserr.Error works, and matches a sentinel error of its kind by errors.Is.
+type Error struct {
+	Path S3Path
+	Err  error
+
+	kind error
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v: %v", e.Path.FullPath(), e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if the target is a sentinel error of the error kind.
+func (e *Error) Is(target error) bool {
+	return e.kind != nil && e.kind == target
+}
+
+// newError returns the err of an operation on the path classified by its AWS code and HTTP status.
+// A nil is returned for a nil err and an already classified error is returned as is.
+func newError(path S3Path, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var s3Err *Error
+	if errors.As(err, &s3Err) {
+		return err
+	}
+
+	return &Error{
+		Path: path,
+		Err:  err,
+		kind: errorKind(err),
+	}
+}
+
+// newETagMismatchError returns an error of a copy to the path which result ETag differs from the expected one.
+func newETagMismatchError(path S3Path, resultETag, expectedETag string) error {
+	return &Error{
+		Path: path,
+		Err:  fmt.Errorf("copy operation result ETag %+v and the source one is %+v", resultETag, expectedETag),
+		kind: ErrETagMismatch,
+	}
+}
+
+// errorKind returns a sentinel error matching the AWS error or nil if there is none.
+func errorKind(err error) error {
+	if reqErr, ok := requestFailure(err); ok {
+		err = reqErr
+	}
+
+	if request.IsErrorThrottle(err) {
+		return ErrThrottled
+	}
+
+	switch errorCode(err) {
+	case awsErrNotFound, awsErrNoSuchKey, awsErrNoSuchBucket, awsErrNoSuchUpload, awsErrNoSuchVersion,
+		awsErrNoSuchLifecycle, awsErrNoSuchBucketPolicy:
+		return ErrNotFound
+	case awsErrAccessDenied:
+		return ErrAccessDenied
+	case awsErrPreconditionFailed, awsErrConditionalConflict:
+		return ErrPreconditionFailed
+	case awsErrNotModified:
+		return ErrNotModified
+	case awsErrBucketExists:
+		return ErrBucketAlreadyExists
+	case awsErrSlowDown:
+		return ErrThrottled
+	}
+
+	switch errorStatus(err) {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusForbidden:
+		return ErrAccessDenied
+	case http.StatusPreconditionFailed:
+		return ErrPreconditionFailed
+	case http.StatusNotModified:
+		return ErrNotModified
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return ErrThrottled
+	}
+
+	return nil
+}
+
+// errorCode returns an AWS error code of an error of any AWS SDK version or an empty string.
+func errorCode(err error) string {
+	if reqErr, ok := requestFailure(err); ok {
+		return reqErr.Code()
+	}
+
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		return awsErr.Code()
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+
+	return ""
+}
+
+// errorStatus returns an HTTP status of a request failed with an error of any AWS SDK version or 0.
+func errorStatus(err error) int {
+	if reqErr, ok := requestFailure(err); ok {
+		return reqErr.StatusCode()
+	}
+
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.HTTPStatusCode()
+	}
+
+	return 0
+}
+
+// requestFailure returns an SDK v1 error of a failed request found in the err chain.
+// SDK v1 errors do not implement Unwrap, so their original errors are walked as well:
+// e.g. s3manager wraps a failed part or upload completion in a MultipartUpload error.
+func requestFailure(err error) (awserr.RequestFailure, bool) {
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		return reqErr, true
+	}
+
+	var origErrs []error
+	var batchErr awserr.BatchedErrors
+	var awsErr awserr.Error
+	switch {
+	case errors.As(err, &batchErr):
+		origErrs = batchErr.OrigErrs()
+	case errors.As(err, &awsErr) && awsErr.OrigErr() != nil:
+		origErrs = []error{awsErr.OrigErr()}
+	}
+
+	for _, origErr := range origErrs {
+		if reqErr, ok := requestFailure(origErr); ok {
+			return reqErr, true
+		}
+	}
+
+	return nil, false
+}
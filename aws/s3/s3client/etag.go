@@ -0,0 +1,77 @@
+package s3client
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // MD5 is required to compute S3 ETags.
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	awsSinglePartCopyMaxSize int64 = 1024 * 1024 * 1024 * 5 // max size of an object copied by a single CopyObject.
+	multipartETagSeparator         = "-"
+)
+
+// compositeETag computes an ETag of a multipart object assembled from parts with the ETags provided:
+// an MD5 of concatenated binary part MD5s followed by "-<parts number>".
+func compositeETag(partETags []string) (string, error) {
+	hash := md5.New() //nolint:gosec // MD5 is required to compute S3 ETags.
+	for _, eTag := range partETags {
+		sum, err := hex.DecodeString(strings.Trim(eTag, `"`))
+		if err != nil {
+			return "", fmt.Errorf("part ETag %v is not an MD5 hash: %w", eTag, err)
+		}
+		hash.Write(sum)
+	}
+
+	return fmt.Sprintf(`"%s%s%d"`, hex.EncodeToString(hash.Sum(nil)), multipartETagSeparator, len(partETags)), nil
+}
+
+// multipartETagParts returns a number of parts of a multipart object by its ETag or 0 for a single part one.
+func multipartETagParts(eTag string) int {
+	eTag = strings.Trim(eTag, `"`)
+
+	idx := strings.LastIndex(eTag, multipartETagSeparator)
+	if idx == -1 {
+		return 0
+	}
+
+	parts, err := strconv.Atoi(eTag[idx+1:])
+	if err != nil {
+		return 0
+	}
+
+	return parts
+}
+
+// multipartPartSize returns a size of the first part of a multipart object. All the parts except the last
+// one have the same size when an object is uploaded by the SDK tools, so it defines the chunk layout.
+func (c *s3Client) multipartPartSize(ctx context.Context, path S3Path, callerPays bool, encryption *Encryption) (int64, error) {
+	params := &s3.HeadObjectInput{
+		Bucket:     aws.String(path.Bucket),
+		Key:        aws.String(path.Key),
+		VersionId:  path.versionID(),
+		PartNumber: aws.Int64(1),
+	}
+	params.RequestPayer = c.opts.requestPayer(callerPays)
+	params.ExpectedBucketOwner = c.opts.bucketOwner()
+
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = encryption.customer()
+
+	head, err := c.awsS3.HeadObjectWithContext(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("error querying first part head: %w", newError(path, err))
+	}
+
+	return aws.Int64Value(head.ContentLength), nil
+}
+
+// partsNumber returns a number of parts an object of the size is split into by the chunk size.
+func partsNumber(size, chunkSize int64) int64 {
+	return (size + chunkSize - 1) / chunkSize
+}
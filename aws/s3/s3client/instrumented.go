@@ -0,0 +1,574 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/FurmanovD/go-kit/prommetrics"
+)
+
+const (
+	// DefaultMetricsSubsystem is a Prometheus subsystem of the client metrics.
+	DefaultMetricsSubsystem = "s3client"
+
+	metricsLabelOperation = "operation"
+	metricsLabelCode      = "code"
+
+	errorCodeCanceled = "Canceled"
+	errorCodeUnknown  = "Unknown"
+)
+
+// DefaultLatencyBuckets are buckets of the operations latency histogram in seconds, from 10ms to ~80s.
+var DefaultLatencyBuckets = prometheus.ExponentialBuckets(0.01, 2, 14)
+
+// instrumentedClient is an S3Client decorator recording metrics of operations of the client it wraps.
+type instrumentedClient struct {
+	backgroundClient
+
+	client S3Client
+
+	latency prommetrics.HistogramVec
+	bytes   prommetrics.CounterVec
+	errors  prommetrics.CounterVec
+}
+
+// NewInstrumentedClient returns a client recording metrics of the client operations by the metrics provided:
+//   - <subsystem>_operation_duration_seconds histogram of an operation latency by the operation;
+//   - <subsystem>_transferred_bytes_total counter of object bytes downloaded or uploaded by the operation;
+//   - <subsystem>_errors_total counter of failed operations by the operation and an AWS error code.
+//
+// DefaultMetricsSubsystem is used when the subsystem is empty. The metrics must be created once per subsystem,
+// so a single instrumented client should wrap all the clients sharing the subsystem.
+// The client should be created after metrics.StartHTTP, so the metrics get its namespace.
+func NewInstrumentedClient(client S3Client, metrics prommetrics.Metrics, subsystem string) S3Client {
+	if subsystem == "" {
+		subsystem = DefaultMetricsSubsystem
+	}
+
+	c := &instrumentedClient{
+		client: client,
+		latency: metrics.NewHistogramVec(
+			subsystem,
+			"operation_duration_seconds",
+			"S3 operation latency in seconds.",
+			DefaultLatencyBuckets,
+			metricsLabelOperation,
+		),
+		bytes: metrics.NewCounterVec(
+			subsystem,
+			"transferred_bytes_total",
+			"Number of S3 object bytes downloaded or uploaded.",
+			metricsLabelOperation,
+		),
+		errors: metrics.NewCounterVec(
+			subsystem,
+			"errors_total",
+			"Number of failed S3 operations by an AWS error code.",
+			metricsLabelOperation,
+			metricsLabelCode,
+		),
+	}
+	c.backgroundClient = backgroundClient{c}
+
+	return c
+}
+
+// observe records a latency of the operation started at the time and its error if any.
+func (c *instrumentedClient) observe(operation string, start time.Time, err *error) {
+	c.latency.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if *err != nil {
+		c.errors.WithLabelValues(operation, metricsErrorCode(*err)).Inc()
+	}
+}
+
+// addBytes records a number of bytes transferred by the operation.
+func (c *instrumentedClient) addBytes(operation string, n int64) {
+	if n > 0 {
+		c.bytes.WithLabelValues(operation).Add(float64(n))
+	}
+}
+
+// metricsErrorCode returns an AWS error code of the err or a generic code when there is none.
+func metricsErrorCode(err error) string {
+	if code := errorCode(err); code != "" {
+		return code
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return errorCodeCanceled
+	}
+
+	return errorCodeUnknown
+}
+
+// CreateBucketWithContext ...
+func (c *instrumentedClient) CreateBucketWithContext(ctx context.Context, bucket string) (location string, err error) {
+	defer c.observe("CreateBucket", time.Now(), &err)
+
+	return c.client.CreateBucketWithContext(ctx, bucket)
+}
+
+// CreateBucketWithOptionsWithContext creates a bucket with the options provided.
+func (c *instrumentedClient) CreateBucketWithOptionsWithContext(
+	ctx context.Context,
+	bucket string,
+	opts CreateBucketOptions,
+) (location string, err error) {
+	defer c.observe("CreateBucketWithOptions", time.Now(), &err)
+
+	return c.client.CreateBucketWithOptionsWithContext(ctx, bucket, opts)
+}
+
+// BucketExistsWithContext returns true if the bucket exists.
+func (c *instrumentedClient) BucketExistsWithContext(ctx context.Context, bucket string) (exists bool, err error) {
+	defer c.observe("BucketExists", time.Now(), &err)
+
+	return c.client.BucketExistsWithContext(ctx, bucket)
+}
+
+// DeleteBucketWithContext deletes the bucket emptying it first if force is set.
+func (c *instrumentedClient) DeleteBucketWithContext(ctx context.Context, bucket string, force bool) (err error) {
+	defer c.observe("DeleteBucket", time.Now(), &err)
+
+	return c.client.DeleteBucketWithContext(ctx, bucket, force)
+}
+
+// GetLifecycleRulesWithContext returns lifecycle rules of the bucket.
+func (c *instrumentedClient) GetLifecycleRulesWithContext(ctx context.Context, bucket string) (rules []LifecycleRule, err error) {
+	defer c.observe("GetLifecycleRules", time.Now(), &err)
+
+	return c.client.GetLifecycleRulesWithContext(ctx, bucket)
+}
+
+// SetLifecycleRulesWithContext replaces lifecycle rules of the bucket.
+func (c *instrumentedClient) SetLifecycleRulesWithContext(ctx context.Context, bucket string, rules []LifecycleRule) (err error) {
+	defer c.observe("SetLifecycleRules", time.Now(), &err)
+
+	return c.client.SetLifecycleRulesWithContext(ctx, bucket, rules)
+}
+
+// GetBucketPolicyWithContext returns a JSON policy of the bucket.
+func (c *instrumentedClient) GetBucketPolicyWithContext(ctx context.Context, bucket string) (policy string, err error) {
+	defer c.observe("GetBucketPolicy", time.Now(), &err)
+
+	return c.client.GetBucketPolicyWithContext(ctx, bucket)
+}
+
+// SetBucketPolicyWithContext replaces a JSON policy of the bucket.
+func (c *instrumentedClient) SetBucketPolicyWithContext(ctx context.Context, bucket, policy string) (err error) {
+	defer c.observe("SetBucketPolicy", time.Now(), &err)
+
+	return c.client.SetBucketPolicyWithContext(ctx, bucket, policy)
+}
+
+// ExistsWithContext returns true if S3 object exists.
+func (c *instrumentedClient) ExistsWithContext(ctx context.Context, obj S3Path) (exists bool, err error) {
+	defer c.observe("Exists", time.Now(), &err)
+
+	return c.client.ExistsWithContext(ctx, obj)
+}
+
+// GetSizeWithContext returns a size in bytes of the object.
+func (c *instrumentedClient) GetSizeWithContext(ctx context.Context, obj S3Path, callerPays bool) (size int64, err error) {
+	defer c.observe("GetSize", time.Now(), &err)
+
+	return c.client.GetSizeWithContext(ctx, obj, callerPays)
+}
+
+// StatWithContext returns all the object attributes.
+func (c *instrumentedClient) StatWithContext(ctx context.Context, obj S3Path, callerPays bool) (info ObjectInfo, err error) {
+	defer c.observe("Stat", time.Now(), &err)
+
+	return c.client.StatWithContext(ctx, obj, callerPays)
+}
+
+// GetTagsWithContext returns tags of the object.
+func (c *instrumentedClient) GetTagsWithContext(ctx context.Context, obj S3Path) (tags map[string]string, err error) {
+	defer c.observe("GetTags", time.Now(), &err)
+
+	return c.client.GetTagsWithContext(ctx, obj)
+}
+
+// SetTagsWithContext replaces tags of the object.
+func (c *instrumentedClient) SetTagsWithContext(ctx context.Context, obj S3Path, tags map[string]string) (err error) {
+	defer c.observe("SetTags", time.Now(), &err)
+
+	return c.client.SetTagsWithContext(ctx, obj, tags)
+}
+
+// GetObjectWithContext returns an S3 object in a byte array view.
+func (c *instrumentedClient) GetObjectWithContext(ctx context.Context, obj S3Path, callerPays bool) (data []byte, err error) {
+	defer c.observe("GetObject", time.Now(), &err)
+
+	data, err = c.client.GetObjectWithContext(ctx, obj, callerPays)
+	c.addBytes("GetObject", int64(len(data)))
+
+	return data, err
+}
+
+// GetObjectStreamWithContext returns a reader of an S3 object content. The bytes are recorded as they are read,
+// the latency covers opening the stream only.
+func (c *instrumentedClient) GetObjectStreamWithContext(
+	ctx context.Context,
+	obj S3Path,
+	callerPays bool,
+	byteRange *ByteRange,
+) (body io.ReadCloser, err error) {
+	defer c.observe("GetObjectStream", time.Now(), &err)
+
+	body, err = c.client.GetObjectStreamWithContext(ctx, obj, callerPays, byteRange)
+	if err != nil {
+		return nil, err
+	}
+
+	return &countingReadCloser{
+		ReadCloser: body,
+		count: func(n int) {
+			c.addBytes("GetObjectStream", int64(n))
+		},
+	}, nil
+}
+
+// DownloadFileWithContext downloads an object to a local file by byte ranges fetched in parallel.
+// The object size is recorded once the file is downloaded.
+func (c *instrumentedClient) DownloadFileWithContext(
+	ctx context.Context,
+	obj S3Path,
+	localPath string,
+	opts DownloadFileOptions,
+) (err error) {
+	defer c.observe("DownloadFile", time.Now(), &err)
+
+	if err = c.client.DownloadFileWithContext(ctx, obj, localPath, opts); err != nil {
+		return err
+	}
+
+	if info, statErr := os.Stat(localPath); statErr == nil {
+		c.addBytes("DownloadFile", info.Size())
+	}
+
+	return nil
+}
+
+// GetObjectIfChangedWithContext returns an S3 object content and attributes if the conditions are met.
+func (c *instrumentedClient) GetObjectIfChangedWithContext(
+	ctx context.Context,
+	obj S3Path,
+	callerPays bool,
+	cond GetConditions,
+) (data []byte, info ObjectInfo, err error) {
+	defer c.observe("GetObjectIfChanged", time.Now(), &err)
+
+	data, info, err = c.client.GetObjectIfChangedWithContext(ctx, obj, callerPays, cond)
+	c.addBytes("GetObjectIfChanged", int64(len(data)))
+
+	return data, info, err
+}
+
+// PutObjectWithContext uploads a body to the S3 path using default upload options.
+func (c *instrumentedClient) PutObjectWithContext(ctx context.Context, obj S3Path, body io.Reader) (err error) {
+	defer c.observe("PutObject", time.Now(), &err)
+
+	body, size := c.countBody("PutObject", body)
+	err = c.client.PutObjectWithContext(ctx, obj, body)
+	if err == nil {
+		c.addBytes("PutObject", size)
+	}
+
+	return err
+}
+
+// UploadWithContext uploads a body to the S3 path.
+func (c *instrumentedClient) UploadWithContext(ctx context.Context, obj S3Path, body io.Reader, opts UploadOptions) (err error) {
+	defer c.observe("Upload", time.Now(), &err)
+
+	body, size := c.countBody("Upload", body)
+	err = c.client.UploadWithContext(ctx, obj, body, opts)
+	if err == nil {
+		c.addBytes("Upload", size)
+	}
+
+	return err
+}
+
+// UploadFileWithContext uploads a local file resuming its interrupted multipart upload if any.
+// The whole file size is recorded as transferred even if a part of it has been uploaded before.
+func (c *instrumentedClient) UploadFileWithContext(
+	ctx context.Context,
+	localPath string,
+	obj S3Path,
+	opts UploadFileOptions,
+) (err error) {
+	defer c.observe("UploadFile", time.Now(), &err)
+
+	if err = c.client.UploadFileWithContext(ctx, localPath, obj, opts); err != nil {
+		return err
+	}
+
+	if info, statErr := os.Stat(localPath); statErr == nil {
+		c.addBytes("UploadFile", info.Size())
+	}
+
+	return nil
+}
+
+// countBody returns a size of a seekable body to record once it is uploaded, so the body is passed
+// to an uploader as is and it does not buffer it. Other bodies are wrapped to record bytes as they are read.
+func (c *instrumentedClient) countBody(operation string, body io.Reader) (io.Reader, int64) {
+	if seeker, ok := body.(io.Seeker); ok {
+		pos, err := seeker.Seek(0, io.SeekCurrent)
+		if err == nil {
+			end, err := seeker.Seek(0, io.SeekEnd)
+			if _, resetErr := seeker.Seek(pos, io.SeekStart); err == nil && resetErr == nil {
+				return body, end - pos
+			}
+		}
+	}
+
+	return &countingReader{
+		Reader: body,
+		count: func(n int) {
+			c.addBytes(operation, int64(n))
+		},
+	}, 0
+}
+
+// DeleteWithContext deletes an S3 object.
+func (c *instrumentedClient) DeleteWithContext(ctx context.Context, obj S3Path) (err error) {
+	defer c.observe("Delete", time.Now(), &err)
+
+	return c.client.DeleteWithContext(ctx, obj)
+}
+
+// DeleteManyWithContext deletes objects by batches and returns objects that were not deleted,
+// they are recorded as errors by their codes.
+func (c *instrumentedClient) DeleteManyWithContext(ctx context.Context, objs []S3Path) (failures []DeleteFailure, err error) {
+	defer c.observe("DeleteMany", time.Now(), &err)
+
+	failures, err = c.client.DeleteManyWithContext(ctx, objs)
+	if err == nil {
+		for _, f := range failures {
+			code := f.Code
+			if code == "" {
+				code = errorCodeUnknown
+			}
+			c.errors.WithLabelValues("DeleteMany", code).Inc()
+		}
+	}
+
+	return failures, err
+}
+
+// DeletePrefixWithContext deletes all objects which keys start with the prefix and returns a number of objects deleted.
+func (c *instrumentedClient) DeletePrefixWithContext(ctx context.Context, prefix S3Path) (deleted int, err error) {
+	defer c.observe("DeletePrefix", time.Now(), &err)
+
+	return c.client.DeletePrefixWithContext(ctx, prefix)
+}
+
+// CopyWithContext copies source to destination.
+func (c *instrumentedClient) CopyWithContext(ctx context.Context, src, dst S3Path, validateEtag, callerPays bool) (err error) {
+	defer c.observe("Copy", time.Now(), &err)
+
+	return c.client.CopyWithContext(ctx, src, dst, validateEtag, callerPays)
+}
+
+// CopyObjectWithContext copies source to destination using the options provided.
+func (c *instrumentedClient) CopyObjectWithContext(ctx context.Context, src, dst S3Path, opts CopyOptions) (err error) {
+	defer c.observe("CopyObject", time.Now(), &err)
+
+	return c.client.CopyObjectWithContext(ctx, src, dst, opts)
+}
+
+// IsSrcNewerWithContext returns true if source exist and newer than destination, or when destination does not exist.
+func (c *instrumentedClient) IsSrcNewerWithContext(ctx context.Context, src, dst S3Path, callerPays bool) (newer bool, err error) {
+	defer c.observe("IsSrcNewer", time.Now(), &err)
+
+	return c.client.IsSrcNewerWithContext(ctx, src, dst, callerPays)
+}
+
+// SyncWithContext copies objects of the src prefix missing or changed in the dst prefix.
+func (c *instrumentedClient) SyncWithContext(ctx context.Context, src, dst S3Path, opts SyncOptions) (report *SyncReport, err error) {
+	defer c.observe("Sync", time.Now(), &err)
+
+	return c.client.SyncWithContext(ctx, src, dst, opts)
+}
+
+// SyncFromDirWithContext uploads files of the local directory missing or changed in the dst prefix.
+func (c *instrumentedClient) SyncFromDirWithContext(
+	ctx context.Context,
+	localDir string,
+	dst S3Path,
+	opts SyncOptions,
+) (report *SyncReport, err error) {
+	defer c.observe("SyncFromDir", time.Now(), &err)
+
+	return c.client.SyncFromDirWithContext(ctx, localDir, dst, opts)
+}
+
+// GetPresignedURLWithContext returns an S3 presigned URL for the given key.
+func (c *instrumentedClient) GetPresignedURLWithContext(ctx context.Context, obj S3Path, duration time.Duration) (url string, err error) {
+	defer c.observe("GetPresignedURL", time.Now(), &err)
+
+	return c.client.GetPresignedURLWithContext(ctx, obj, duration)
+}
+
+// GetPresignedPutURLWithContext returns an S3 presigned URL to upload the object by a PUT request.
+func (c *instrumentedClient) GetPresignedPutURLWithContext(
+	ctx context.Context,
+	obj S3Path,
+	duration time.Duration,
+	contentType, contentMD5 string,
+) (url string, err error) {
+	defer c.observe("GetPresignedPutURL", time.Now(), &err)
+
+	return c.client.GetPresignedPutURLWithContext(ctx, obj, duration, contentType, contentMD5)
+}
+
+// GetPresignedDeleteURLWithContext returns an S3 presigned URL to delete the object by a DELETE request.
+func (c *instrumentedClient) GetPresignedDeleteURLWithContext(
+	ctx context.Context,
+	obj S3Path,
+	duration time.Duration,
+) (url string, err error) {
+	defer c.observe("GetPresignedDeleteURL", time.Now(), &err)
+
+	return c.client.GetPresignedDeleteURLWithContext(ctx, obj, duration)
+}
+
+// GetPresignedPostWithContext returns a URL and form fields of a browser POST upload to the object path.
+func (c *instrumentedClient) GetPresignedPostWithContext(
+	ctx context.Context,
+	obj S3Path,
+	duration time.Duration,
+	opts PostPolicyOptions,
+) (post *PresignedPost, err error) {
+	defer c.observe("GetPresignedPost", time.Now(), &err)
+
+	return c.client.GetPresignedPostWithContext(ctx, obj, duration, opts)
+}
+
+// GetETagWithContext returns an ETag of the object.
+func (c *instrumentedClient) GetETagWithContext(ctx context.Context, obj S3Path) (eTag string, err error) {
+	defer c.observe("GetETag", time.Now(), &err)
+
+	return c.client.GetETagWithContext(ctx, obj)
+}
+
+// stat implements sourceQuerier, so the client can be a copy source client.
+func (c *instrumentedClient) stat(
+	ctx context.Context,
+	path S3Path,
+	callerPays bool,
+	encryption *Encryption,
+) (info ObjectInfo, err error) {
+	defer c.observe("Stat", time.Now(), &err)
+
+	if source, ok := c.client.(sourceQuerier); ok {
+		return source.stat(ctx, path, callerPays, encryption)
+	}
+
+	return c.client.StatWithContext(ctx, path, callerPays)
+}
+
+// multipartPartSize implements sourceQuerier, so the client can be a copy source client.
+func (c *instrumentedClient) multipartPartSize(
+	ctx context.Context,
+	path S3Path,
+	callerPays bool,
+	encryption *Encryption,
+) (size int64, err error) {
+	defer c.observe("Stat", time.Now(), &err)
+
+	source, ok := c.client.(sourceQuerier)
+	if !ok {
+		return 0, fmt.Errorf("client %T cannot query a part size of %v", c.client, path)
+	}
+
+	return source.multipartPartSize(ctx, path, callerPays, encryption)
+}
+
+// ListWithContext returns an iterator over objects of the prefix, every page request is recorded.
+func (c *instrumentedClient) ListWithContext(ctx context.Context, prefix S3Path, delimiter string) *ObjectIterator {
+	return c.instrumentIterator("List", c.client.ListWithContext(ctx, prefix, delimiter))
+}
+
+// ListVersionsWithContext returns an iterator over all versions of objects of the prefix,
+// every page request is recorded.
+func (c *instrumentedClient) ListVersionsWithContext(ctx context.Context, prefix S3Path) *ObjectIterator {
+	return c.instrumentIterator("ListVersions", c.client.ListVersionsWithContext(ctx, prefix))
+}
+
+// instrumentIterator records page requests of the iterator as the operation.
+func (c *instrumentedClient) instrumentIterator(operation string, it *ObjectIterator) *ObjectIterator {
+	fetch := it.fetch
+	it.fetch = func(ctx context.Context, token string) (page []ObjectInfo, nextToken string, err error) {
+		defer c.observe(operation, time.Now(), &err)
+
+		return fetch(ctx, token)
+	}
+
+	return it
+}
+
+// ListIncompleteUploadsWithContext returns all incomplete multipart uploads of the bucket.
+func (c *instrumentedClient) ListIncompleteUploadsWithContext(
+	ctx context.Context,
+	bucket, prefix string,
+) (uploads []IncompleteUpload, err error) {
+	defer c.observe("ListIncompleteUploads", time.Now(), &err)
+
+	return c.client.ListIncompleteUploadsWithContext(ctx, bucket, prefix)
+}
+
+// AbortUploadWithContext aborts an incomplete multipart upload.
+func (c *instrumentedClient) AbortUploadWithContext(ctx context.Context, upload IncompleteUpload) (err error) {
+	defer c.observe("AbortUpload", time.Now(), &err)
+
+	return c.client.AbortUploadWithContext(ctx, upload)
+}
+
+// AbortStaleUploadsWithContext aborts all incomplete multipart uploads of the bucket initiated more than olderThan ago.
+func (c *instrumentedClient) AbortStaleUploadsWithContext(
+	ctx context.Context,
+	bucket string,
+	olderThan time.Duration,
+) (aborted int, err error) {
+	defer c.observe("AbortStaleUploads", time.Now(), &err)
+
+	return c.client.AbortStaleUploadsWithContext(ctx, bucket, olderThan)
+}
+
+// countingReader calls count with a number of bytes of every read.
+type countingReader struct {
+	io.Reader
+	count func(n int)
+}
+
+// Read implements io.Reader interface.
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.count(n)
+
+	return n, err
+}
+
+// countingReadCloser calls count with a number of bytes of every read.
+type countingReadCloser struct {
+	io.ReadCloser
+	count func(n int)
+}
+
+// Read implements io.Reader interface.
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.count(n)
+
+	return n, err
+}
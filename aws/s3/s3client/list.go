@@ -0,0 +1,147 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// objectPageFunc fetches a page of a listing started from the continuation token.
+// It returns an empty next token for the last page.
+type objectPageFunc func(ctx context.Context, token string) (page []ObjectInfo, nextToken string, err error)
+
+// ObjectIterator iterates over objects of a listing fetching its pages on demand.
+//
+//	it := client.List(prefix, "")
+//	for it.Next() {
+//		obj := it.Object()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type ObjectIterator struct {
+	ctx   context.Context
+	fetch objectPageFunc
+
+	page    []ObjectInfo
+	idx     int
+	token   string
+	started bool
+	err     error
+}
+
+// newObjectIterator returns an iterator fetching pages by the fetch function.
+func newObjectIterator(ctx context.Context, fetch objectPageFunc) *ObjectIterator {
+	return &ObjectIterator{
+		ctx:   ctx,
+		fetch: fetch,
+		idx:   -1,
+	}
+}
+
+// Next advances the iterator to the next object. It returns false when there are no more objects
+// or an error occurred.
+func (it *ObjectIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.idx++
+	for it.idx >= len(it.page) {
+		if it.started && it.token == "" {
+			return false
+		}
+
+		it.page, it.token, it.err = it.fetch(it.ctx, it.token)
+		it.started = true
+		it.idx = 0
+		if it.err != nil {
+			it.page = nil
+			return false
+		}
+	}
+
+	return true
+}
+
+// Object returns the current object.
+func (it *ObjectIterator) Object() ObjectInfo {
+	if it.idx < 0 || it.idx >= len(it.page) {
+		return ObjectInfo{}
+	}
+
+	return it.page[it.idx]
+}
+
+// Err returns an error occurred while listing if any.
+func (it *ObjectIterator) Err() error {
+	return it.err
+}
+
+// All reads the rest of objects of the iterator.
+func (it *ObjectIterator) All() ([]ObjectInfo, error) {
+	objects := []ObjectInfo{}
+	for it.Next() {
+		objects = append(objects, it.Object())
+	}
+
+	return objects, it.Err()
+}
+
+// ListWithContext returns an iterator over objects of the prefix.Bucket which keys start with the prefix.Key.
+// Keys containing the delimiter after the prefix are rolled up into common prefixes when the delimiter is set.
+func (c *s3Client) ListWithContext(ctx context.Context, prefix S3Path, delimiter string) *ObjectIterator {
+	return newObjectIterator(ctx, func(ctx context.Context, token string) ([]ObjectInfo, string, error) {
+		params := &s3.ListObjectsV2Input{
+			Bucket:              aws.String(prefix.Bucket),
+			RequestPayer:        c.opts.requestPayer(false),
+			ExpectedBucketOwner: c.opts.bucketOwner(),
+		}
+		if prefix.Key != "" {
+			params.Prefix = aws.String(prefix.Key)
+		}
+		if delimiter != "" {
+			params.Delimiter = aws.String(delimiter)
+		}
+		if token != "" {
+			params.ContinuationToken = aws.String(token)
+		}
+
+		resp, err := c.awsS3.ListObjectsV2WithContext(ctx, params)
+		if err != nil {
+			return nil, "", fmt.Errorf("error listing objects: %w", newError(prefix, err))
+		}
+
+		page := make([]ObjectInfo, 0, len(resp.CommonPrefixes)+len(resp.Contents))
+		for _, p := range resp.CommonPrefixes {
+			page = append(page, ObjectInfo{
+				Path: S3Path{
+					Bucket: prefix.Bucket,
+					Key:    aws.StringValue(p.Prefix),
+				},
+				IsPrefix: true,
+			})
+		}
+
+		for _, obj := range resp.Contents {
+			page = append(page, ObjectInfo{
+				Path: S3Path{
+					Bucket: prefix.Bucket,
+					Key:    aws.StringValue(obj.Key),
+				},
+				Size:         aws.Int64Value(obj.Size),
+				ETag:         aws.StringValue(obj.ETag),
+				LastModified: aws.TimeValue(obj.LastModified),
+				StorageClass: aws.StringValue(obj.StorageClass),
+			})
+		}
+
+		nextToken := ""
+		if aws.BoolValue(resp.IsTruncated) {
+			nextToken = aws.StringValue(resp.NextContinuationToken)
+		}
+
+		return page, nextToken, nil
+	})
+}
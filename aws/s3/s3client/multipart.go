@@ -0,0 +1,105 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// IncompleteUpload describes a multipart upload that was initiated but neither completed nor aborted.
+type IncompleteUpload struct {
+	Path      S3Path
+	UploadID  string
+	Initiated time.Time
+}
+
+// ListIncompleteUploadsWithContext returns all incomplete multipart uploads of the bucket
+// which keys start with the prefix.
+func (c *s3Client) ListIncompleteUploadsWithContext(ctx context.Context, bucket, prefix string) ([]IncompleteUpload, error) {
+	params := &s3.ListMultipartUploadsInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}
+	if prefix != "" {
+		params.Prefix = aws.String(prefix)
+	}
+
+	uploads := []IncompleteUpload{}
+	err := c.awsS3.ListMultipartUploadsPagesWithContext(
+		ctx,
+		params,
+		func(page *s3.ListMultipartUploadsOutput, lastPage bool) bool {
+			for _, u := range page.Uploads {
+				uploads = append(uploads, IncompleteUpload{
+					Path: S3Path{
+						Bucket: bucket,
+						Key:    aws.StringValue(u.Key),
+					},
+					UploadID:  aws.StringValue(u.UploadId),
+					Initiated: aws.TimeValue(u.Initiated),
+				})
+			}
+
+			return true
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error listing multipart uploads: %w", newError(S3Path{Bucket: bucket}, err))
+	}
+
+	return uploads, nil
+}
+
+// AbortUploadWithContext aborts an incomplete multipart upload and deletes its uploaded parts.
+func (c *s3Client) AbortUploadWithContext(ctx context.Context, upload IncompleteUpload) error {
+	return c.abortMultipartUpload(ctx, upload.Path, upload.UploadID)
+}
+
+// AbortStaleUploadsWithContext aborts all incomplete multipart uploads of the bucket initiated
+// more than olderThan ago and returns a number of uploads aborted.
+func (c *s3Client) AbortStaleUploadsWithContext(ctx context.Context, bucket string, olderThan time.Duration) (int, error) {
+	return abortStaleUploads(ctx, c, bucket, olderThan)
+}
+
+// abortStaleUploads aborts stale uploads of the bucket using the client provided.
+func abortStaleUploads(ctx context.Context, client S3ClientCtx, bucket string, olderThan time.Duration) (int, error) {
+	uploads, err := client.ListIncompleteUploadsWithContext(ctx, bucket, "")
+	if err != nil {
+		return 0, err
+	}
+
+	aborted := 0
+	threshold := time.Now().Add(-olderThan)
+	for _, u := range uploads {
+		if !u.Initiated.Before(threshold) {
+			continue
+		}
+
+		if err = client.AbortUploadWithContext(ctx, u); err != nil {
+			return aborted, err
+		}
+		aborted++
+	}
+
+	return aborted, nil
+}
+
+// abortMultipartUpload aborts a multipart upload by its ID.
+func (c *s3Client) abortMultipartUpload(ctx context.Context, path S3Path, uploadID string) error {
+	_, err := c.awsS3.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(path.Bucket),
+		Key:      aws.String(path.Key),
+		UploadId: aws.String(uploadID),
+
+		RequestPayer:        c.opts.requestPayer(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("error aborting multipart upload %v : %w", uploadID, newError(path, err))
+	}
+
+	return nil
+}
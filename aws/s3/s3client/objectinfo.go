@@ -0,0 +1,70 @@
+package s3client
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// ObjectInfo contains attributes of an S3 object.
+type ObjectInfo struct {
+	Path         S3Path
+	Size         int64
+	ETag         string
+	LastModified time.Time
+	StorageClass string
+	// IsPrefix is set for a common prefix returned by a listing with a delimiter,
+	// only Path is filled in this case.
+	IsPrefix bool
+	// IsLatest and IsDeleteMarker are returned by ListVersions only, Path.VersionID is set in this case.
+	IsLatest       bool
+	IsDeleteMarker bool
+
+	// Attributes below are returned by Stat and GetObjectIfChanged only.
+	ContentType     string
+	ContentEncoding string
+	CacheControl    string
+	Metadata        map[string]string
+}
+
+// newObjectInfoFromHead returns attributes of an object by its HEAD response.
+func newObjectInfoFromHead(path S3Path, head *s3.HeadObjectOutput) ObjectInfo {
+	storageClass := aws.StringValue(head.StorageClass)
+	if storageClass == "" {
+		// HEAD omits the storage class of STANDARD objects.
+		storageClass = s3.StorageClassStandard
+	}
+
+	return ObjectInfo{
+		Path:            path,
+		Size:            aws.Int64Value(head.ContentLength),
+		ETag:            aws.StringValue(head.ETag),
+		LastModified:    aws.TimeValue(head.LastModified),
+		StorageClass:    storageClass,
+		ContentType:     aws.StringValue(head.ContentType),
+		ContentEncoding: aws.StringValue(head.ContentEncoding),
+		CacheControl:    aws.StringValue(head.CacheControl),
+		Metadata:        aws.StringValueMap(head.Metadata),
+	}
+}
+
+// newObjectInfoFromGet returns attributes of an object by its GET response.
+func newObjectInfoFromGet(path S3Path, resp *s3.GetObjectOutput) ObjectInfo {
+	storageClass := aws.StringValue(resp.StorageClass)
+	if storageClass == "" {
+		storageClass = s3.StorageClassStandard
+	}
+
+	return ObjectInfo{
+		Path:            path,
+		Size:            aws.Int64Value(resp.ContentLength),
+		ETag:            aws.StringValue(resp.ETag),
+		LastModified:    aws.TimeValue(resp.LastModified),
+		StorageClass:    storageClass,
+		ContentType:     aws.StringValue(resp.ContentType),
+		ContentEncoding: aws.StringValue(resp.ContentEncoding),
+		CacheControl:    aws.StringValue(resp.CacheControl),
+		Metadata:        aws.StringValueMap(resp.Metadata),
+	}
+}
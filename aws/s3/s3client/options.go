@@ -0,0 +1,84 @@
+package s3client
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// ClientOption sets an optional parameter of a client.
+type ClientOption func(*clientOptions)
+
+// clientOptions contains optional parameters applied to all the client requests.
+type clientOptions struct {
+	encryption          *Encryption
+	requesterPays       bool
+	expectedBucketOwner string
+	storageClass        string
+}
+
+// newClientOptions returns client options with the options provided applied.
+func newClientOptions(opts ...ClientOption) clientOptions {
+	o := clientOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithEncryption sets a server-side encryption of objects written by the client.
+// A customer key (SSE-C) is also used to read objects and as a copy source key.
+func WithEncryption(encryption *Encryption) ClientOption {
+	return func(o *clientOptions) {
+		o.encryption = encryption
+	}
+}
+
+// WithRequesterPays makes the client pay for all its requests, so requester pays buckets can be accessed
+// by any method, not only the ones accepting a callerPays argument.
+func WithRequesterPays() ClientOption {
+	return func(o *clientOptions) {
+		o.requesterPays = true
+	}
+}
+
+// WithExpectedBucketOwner sets an account ID all the buckets accessed by the client, including copy sources,
+// must belong to. A request to a bucket of another account fails with an access denied error.
+func WithExpectedBucketOwner(accountID string) ClientOption {
+	return func(o *clientOptions) {
+		o.expectedBucketOwner = accountID
+	}
+}
+
+// WithStorageClass sets a storage class of objects written by the client, e.g. s3.StorageClassStandardIa.
+func WithStorageClass(storageClass string) ClientOption {
+	return func(o *clientOptions) {
+		o.storageClass = storageClass
+	}
+}
+
+// requestPayer returns a request payer header value of a request, callerPays is a per-call setting.
+func (o clientOptions) requestPayer(callerPays bool) *string {
+	if callerPays || o.requesterPays {
+		return aws.String(payerRequester)
+	}
+
+	return nil
+}
+
+// bucketOwner returns an expected bucket owner header value of a request.
+func (o clientOptions) bucketOwner() *string {
+	if o.expectedBucketOwner == "" {
+		return nil
+	}
+
+	return aws.String(o.expectedBucketOwner)
+}
+
+// storageClassValue returns a storage class header value of a write request.
+func (o clientOptions) storageClassValue() *string {
+	if o.storageClass == "" {
+		return nil
+	}
+
+	return aws.String(o.storageClass)
+}
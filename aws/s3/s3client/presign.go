@@ -0,0 +1,250 @@
+package s3client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	postPolicyAlgorithm    = "AWS4-HMAC-SHA256"
+	postPolicyService      = "s3"
+	postPolicyTerminator   = "aws4_request"
+	postPolicyDateFormat   = "20060102"
+	postPolicyTimeFormat   = "20060102T150405Z"
+	postPolicyExpiryFormat = "2006-01-02T15:04:05.000Z"
+	postPolicyFilenameVar  = "${filename}"
+)
+
+// PostPolicyOptions contains constraints of a browser POST upload.
+type PostPolicyOptions struct {
+	// MinSize and MaxSize limit a size of an uploaded object, no limit is set when MaxSize is 0.
+	MinSize int64
+	MaxSize int64
+	// ContentType is a required MIME type of an uploaded object, any type is allowed when empty.
+	ContentType string
+	// KeyPrefix allows any key starting with the path key, the uploaded file name is appended to the key.
+	KeyPrefix bool
+}
+
+// PresignedPost contains a URL and form fields a browser has to POST with a file to upload it.
+type PresignedPost struct {
+	URL    string
+	Fields map[string]string
+}
+
+// GetPresignedURLWithContext returns an S3 presigned URL for the given key.
+func (c *s3Client) GetPresignedURLWithContext(ctx context.Context, path S3Path, duration time.Duration) (string, error) {
+	req, _ := c.awsS3.GetObjectRequest(&s3.GetObjectInput{
+		Bucket:              aws.String(path.Bucket),
+		Key:                 aws.String(path.Key),
+		VersionId:           path.versionID(),
+		RequestPayer:        c.opts.requestPayer(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+
+	return presign(ctx, req, path, duration)
+}
+
+// GetPresignedPutURLWithContext returns an S3 presigned URL to upload the object by a PUT request.
+// Non-empty contentType and base64 encoded contentMD5 must be sent as the request headers with the same values.
+func (c *s3Client) GetPresignedPutURLWithContext(
+	ctx context.Context,
+	path S3Path,
+	duration time.Duration,
+	contentType, contentMD5 string,
+) (string, error) {
+	params := &s3.PutObjectInput{
+		Bucket:              aws.String(path.Bucket),
+		Key:                 aws.String(path.Key),
+		RequestPayer:        c.opts.requestPayer(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+		StorageClass:        c.opts.storageClassValue(),
+	}
+	if contentType != "" {
+		params.ContentType = aws.String(contentType)
+	}
+	if contentMD5 != "" {
+		params.ContentMD5 = aws.String(contentMD5)
+	}
+
+	req, _ := c.awsS3.PutObjectRequest(params)
+
+	return presign(ctx, req, path, duration)
+}
+
+// GetPresignedDeleteURLWithContext returns an S3 presigned URL to delete the object by a DELETE request.
+func (c *s3Client) GetPresignedDeleteURLWithContext(ctx context.Context, path S3Path, duration time.Duration) (string, error) {
+	req, _ := c.awsS3.DeleteObjectRequest(&s3.DeleteObjectInput{
+		Bucket:              aws.String(path.Bucket),
+		Key:                 aws.String(path.Key),
+		VersionId:           path.versionID(),
+		RequestPayer:        c.opts.requestPayer(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+
+	return presign(ctx, req, path, duration)
+}
+
+// GetPresignedPostWithContext returns a URL and form fields of a browser POST upload to the object path.
+func (c *s3Client) GetPresignedPostWithContext(
+	ctx context.Context,
+	path S3Path,
+	duration time.Duration,
+	opts PostPolicyOptions,
+) (*PresignedPost, error) {
+	// a bucket request is built to get the bucket URL with the client addressing style.
+	req, _ := c.awsS3.HeadBucketRequest(&s3.HeadBucketInput{
+		Bucket: aws.String(path.Bucket),
+	})
+	req.SetContext(ctx)
+	if err := req.Build(); err != nil {
+		return nil, fmt.Errorf("error building a POST request %v : %w", path, err)
+	}
+
+	creds, err := c.awsS3.Config.Credentials.GetWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting credentials to sign a POST policy %v : %w", path, err)
+	}
+
+	bucketURL := *req.HTTPRequest.URL
+	bucketURL.RawQuery = ""
+
+	return newPresignedPost(bucketURL.String(), path, duration, opts, postPolicySigner{
+		accessKeyID:     creds.AccessKeyID,
+		secretAccessKey: creds.SecretAccessKey,
+		sessionToken:    creds.SessionToken,
+		region:          aws.StringValue(c.awsS3.Config.Region),
+		storageClass:    c.opts.storageClass,
+	})
+}
+
+// postPolicySigner contains parameters of a POST policy signature.
+type postPolicySigner struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	region          string
+	storageClass    string
+}
+
+// newPresignedPost returns form fields of a POST upload to the bucket URL with a policy signed by the signer.
+// nolint:funlen
+func newPresignedPost(
+	bucketURL string,
+	path S3Path,
+	duration time.Duration,
+	opts PostPolicyOptions,
+	signer postPolicySigner,
+) (*PresignedPost, error) {
+	now := time.Now().UTC()
+	scope := strings.Join(
+		[]string{now.Format(postPolicyDateFormat), signer.region, postPolicyService, postPolicyTerminator},
+		"/",
+	)
+
+	fields := map[string]string{
+		"key":              path.Key,
+		"x-amz-algorithm":  postPolicyAlgorithm,
+		"x-amz-credential": signer.accessKeyID + "/" + scope,
+		"x-amz-date":       now.Format(postPolicyTimeFormat),
+	}
+	conditions := []interface{}{
+		map[string]string{"bucket": path.Bucket},
+	}
+
+	if opts.KeyPrefix {
+		fields["key"] = path.Key + postPolicyFilenameVar
+		conditions = append(conditions, []string{"starts-with", "$key", path.Key})
+	} else {
+		conditions = append(conditions, map[string]string{"key": path.Key})
+	}
+
+	if opts.MaxSize > 0 {
+		conditions = append(conditions, []interface{}{"content-length-range", opts.MinSize, opts.MaxSize})
+	}
+
+	if opts.ContentType != "" {
+		fields["Content-Type"] = opts.ContentType
+		conditions = append(conditions, map[string]string{"Content-Type": opts.ContentType})
+	}
+
+	if signer.sessionToken != "" {
+		fields["x-amz-security-token"] = signer.sessionToken
+	}
+
+	if signer.storageClass != "" {
+		fields["x-amz-storage-class"] = signer.storageClass
+	}
+
+	for _, name := range []string{
+		"x-amz-algorithm",
+		"x-amz-credential",
+		"x-amz-date",
+		"x-amz-security-token",
+		"x-amz-storage-class",
+	} {
+		if value, ok := fields[name]; ok {
+			conditions = append(conditions, map[string]string{name: value})
+		}
+	}
+
+	policy, err := json.Marshal(map[string]interface{}{
+		"expiration": now.Add(duration).Format(postPolicyExpiryFormat),
+		"conditions": conditions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding a POST policy %v : %w", path, err)
+	}
+
+	fields["policy"] = base64.StdEncoding.EncodeToString(policy)
+	fields["x-amz-signature"] = hex.EncodeToString(
+		hmacSHA256(postPolicySigningKey(signer.secretAccessKey, now, signer.region), []byte(fields["policy"])),
+	)
+
+	return &PresignedPost{
+		URL:    bucketURL,
+		Fields: fields,
+	}, nil
+}
+
+// presign returns a presigned URL of the request returning its build error if any.
+func presign(ctx context.Context, req *request.Request, path S3Path, duration time.Duration) (string, error) {
+	if req.Error != nil {
+		return "", fmt.Errorf("error building a request to presign %v : %w", path, req.Error)
+	}
+	req.SetContext(ctx)
+
+	url, err := req.Presign(duration)
+	if err != nil {
+		return "", fmt.Errorf("error presigning a request %v : %w", path, err)
+	}
+
+	return url, nil
+}
+
+// postPolicySigningKey derives a SigV4 signing key.
+func postPolicySigningKey(secret string, t time.Time, region string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), []byte(t.Format(postPolicyDateFormat)))
+	key = hmacSHA256(key, []byte(region))
+	key = hmacSHA256(key, []byte(postPolicyService))
+
+	return hmacSHA256(key, []byte(postPolicyTerminator))
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	hash := hmac.New(sha256.New, key)
+	hash.Write(data)
+
+	return hash.Sum(nil)
+}
@@ -2,12 +2,10 @@
 package s3client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"sort"
-	"sync"
-	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -25,14 +23,21 @@ const (
 )
 
 type s3Client struct {
+	backgroundClient
+
 	awsS3 *s3.S3
+	opts  clientOptions
 }
 
 // NewClientFromS3 sets the AWS S3 connection and returns an S3Client interface.
-func NewClientFromS3(awsS3client *s3.S3) S3Client {
-	return &s3Client{
+func NewClientFromS3(awsS3client *s3.S3, opts ...ClientOption) S3Client {
+	c := &s3Client{
 		awsS3: awsS3client,
+		opts:  newClientOptions(opts...),
 	}
+	c.backgroundClient = backgroundClient{c}
+
+	return c
 }
 
 // S3 returns an AWS S3 connection that was used when creating the object.
@@ -40,69 +45,109 @@ func (c *s3Client) S3() *s3.S3 {
 	return c.awsS3
 }
 
-// CreateBucket ...
-func (c *s3Client) CreateBucket(bucket string) (string, error) {
-	bckt, err := c.awsS3.CreateBucket(&s3.CreateBucketInput{
-		Bucket: aws.String(bucket),
-	})
+// GetObjectWithContext returns an S3 object in a byte array view.
+func (c *s3Client) GetObjectWithContext(ctx context.Context, path S3Path, callerPays bool) ([]byte, error) {
+	body, err := c.GetObjectStreamWithContext(ctx, path, callerPays, nil)
 	if err != nil {
-		var awsErr awserr.RequestFailure
-		if ok := errors.As(err, &awsErr); ok && awsErr.StatusCode() == 409 /*already exists*/ {
-			return "/" + bucket, nil
-		}
-
-		return "", err
+		return nil, err
 	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
 
-	return *bckt.Location, nil
+// GetObjectStreamWithContext returns a reader of an S3 object content. A nil byteRange means the whole object.
+// The caller is responsible for closing the reader returned.
+func (c *s3Client) GetObjectStreamWithContext(
+	ctx context.Context,
+	path S3Path,
+	callerPays bool,
+	byteRange *ByteRange,
+) (io.ReadCloser, error) {
+	return c.getObjectRange(ctx, path, callerPays, byteRange, "")
 }
 
-// Get returns an S3 object in a byte array view.
-func (c *s3Client) GetObject(path S3Path, callerPays bool) ([]byte, error) {
+// getObjectRange returns a reader of a byte range of an S3 object. ErrPreconditionFailed is returned
+// if ifMatch is set and the object ETag differs.
+func (c *s3Client) getObjectRange(
+	ctx context.Context,
+	path S3Path,
+	callerPays bool,
+	byteRange *ByteRange,
+	ifMatch string,
+) (io.ReadCloser, error) {
 	params := &s3.GetObjectInput{
-		Bucket: aws.String(path.Bucket),
-		Key:    aws.String(path.Key),
+		Bucket:    aws.String(path.Bucket),
+		Key:       aws.String(path.Key),
+		VersionId: path.versionID(),
+	}
+	params.RequestPayer = c.opts.requestPayer(callerPays)
+	params.ExpectedBucketOwner = c.opts.bucketOwner()
+
+	if byteRange != nil {
+		params.Range = aws.String(byteRange.String())
 	}
+	if ifMatch != "" {
+		params.IfMatch = aws.String(ifMatch)
+	}
+
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = c.opts.encryption.customer()
 
-	if callerPays {
-		params.RequestPayer = aws.String(payerRequester)
+	resp, err := c.awsS3.GetObjectWithContext(ctx, params)
+	if err != nil {
+		return nil, newError(path, err)
 	}
 
-	resp, err := c.awsS3.GetObject(params)
+	return resp.Body, nil
+}
+
+// GetSizeWithContext returns a size in bytes of the object.
+func (c *s3Client) GetSizeWithContext(ctx context.Context, path S3Path, callerPays bool) (int64, error) {
+	info, err := c.StatWithContext(ctx, path, callerPays)
 	if err != nil {
-		return nil, err
+		return -1, err
 	}
 
-	return io.ReadAll(resp.Body)
+	return info.Size, nil
 }
 
-// GetSize returns a size in bytes of the object.
-func (c *s3Client) GetSize(path S3Path, callerPays bool) (int64, error) {
+// StatWithContext returns all the object attributes by a single HEAD request.
+func (c *s3Client) StatWithContext(ctx context.Context, path S3Path, callerPays bool) (ObjectInfo, error) {
+	return c.stat(ctx, path, callerPays, c.opts.encryption)
+}
+
+// stat returns the object attributes using an SSE-C key of the encryption if any.
+func (c *s3Client) stat(ctx context.Context, path S3Path, callerPays bool, encryption *Encryption) (ObjectInfo, error) {
 	params := &s3.HeadObjectInput{
-		Bucket: aws.String(path.Bucket),
-		Key:    aws.String(path.Key),
+		Bucket:    aws.String(path.Bucket),
+		Key:       aws.String(path.Key),
+		VersionId: path.versionID(),
 	}
+	params.RequestPayer = c.opts.requestPayer(callerPays)
+	params.ExpectedBucketOwner = c.opts.bucketOwner()
 
-	if callerPays {
-		params.RequestPayer = aws.String(payerRequester)
-	}
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = encryption.customer()
 
-	head, err := c.awsS3.HeadObject(params)
+	head, err := c.awsS3.HeadObjectWithContext(ctx, params)
 	if err != nil {
-		return -1, err
+		return ObjectInfo{}, newError(path, err)
 	}
 
-	return *head.ContentLength, nil
+	return newObjectInfoFromHead(path, head), nil
 }
 
-// Exists returns true if S3 object exists.
-func (c *s3Client) Exists(path S3Path) (bool, error) {
+// ExistsWithContext returns true if S3 object exists.
+func (c *s3Client) ExistsWithContext(ctx context.Context, path S3Path) (bool, error) {
 	headParams := &s3.HeadObjectInput{
-		Bucket: aws.String(path.Bucket),
-		Key:    aws.String(path.Key),
+		Bucket:    aws.String(path.Bucket),
+		Key:       aws.String(path.Key),
+		VersionId: path.versionID(),
 	}
+	headParams.RequestPayer = c.opts.requestPayer(false)
+	headParams.ExpectedBucketOwner = c.opts.bucketOwner()
+	headParams.SSECustomerAlgorithm, headParams.SSECustomerKey, headParams.SSECustomerKeyMD5 = c.opts.encryption.customer()
 
-	_, err := c.awsS3.HeadObject(headParams)
+	_, err := c.awsS3.HeadObjectWithContext(ctx, headParams)
 	if err != nil {
 		var awsErr awserr.Error
 		if ok := errors.As(err, &awsErr); ok {
@@ -111,20 +156,23 @@ func (c *s3Client) Exists(path S3Path) (bool, error) {
 			}
 		}
 
-		return false, err
+		return false, newError(path, err)
 	}
 
 	return true, nil
 }
 
-// Delete deletes an S3 object.
-func (c *s3Client) Delete(path S3Path) error {
+// DeleteWithContext deletes an S3 object.
+func (c *s3Client) DeleteWithContext(ctx context.Context, path S3Path) error {
 	params := &s3.DeleteObjectInput{
-		Bucket: aws.String(path.Bucket),
-		Key:    aws.String(path.Key),
+		Bucket:              aws.String(path.Bucket),
+		Key:                 aws.String(path.Key),
+		VersionId:           path.versionID(),
+		RequestPayer:        c.opts.requestPayer(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
 	}
 
-	_, err := c.awsS3.DeleteObject(params)
+	_, err := c.awsS3.DeleteObjectWithContext(ctx, params)
 	if err != nil {
 		var awsErr awserr.Error
 		if ok := errors.As(err, &awsErr); ok {
@@ -133,59 +181,66 @@ func (c *s3Client) Delete(path S3Path) error {
 			}
 		}
 
-		return err
+		return newError(path, err)
 	}
 
 	return nil
 }
 
-// Copy copies source to destination and checks if required the result integrity
+// CopyWithContext copies source to destination and checks if required the result integrity
 // by comparing an ETag of source and destination.
-func (c *s3Client) Copy(src, dst S3Path, validateEtag, callerPays bool) error {
-	return c.copyObject(src, dst, validateEtag, callerPays)
+func (c *s3Client) CopyWithContext(ctx context.Context, src, dst S3Path, validateEtag, callerPays bool) error {
+	return c.CopyObjectWithContext(ctx, src, dst, CopyOptions{
+		ValidateETag: validateEtag,
+		CallerPays:   callerPays,
+	})
 }
 
-// copyObject copies source to destination.
-func (c *s3Client) copyObject(src, dst S3Path, validateEtag bool, callerPays bool) error {
-	srcSize, err := c.GetSize(src, callerPays)
-	if err != nil {
-		return err
-	}
-
-	eTag := ""
-	if validateEtag {
-		eTag, err = c.GetETag(src)
-		if err != nil {
-			return err
-		}
-	}
-
-	if srcSize > awsSinglePartCopyLimit {
-		if validateEtag {
-			return fmt.Errorf("file size %v requires to use a miltipart copy operation that cannot be verified using ETags", srcSize)
-		}
-
-		return c.copyMultipartInt(src, dst, srcSize, DefaultMultipartChunkSize, callerPays)
-	}
+// CopyObjectWithContext copies source to destination using the options provided.
+func (c *s3Client) CopyObjectWithContext(ctx context.Context, src, dst S3Path, opts CopyOptions) error {
+	return copyObject(ctx, c, src, dst, opts, c.opts.encryption)
+}
 
+// copySinglePart copies source to destination by a single CopyObject request.
+func (c *s3Client) copySinglePart(ctx context.Context, src, dst S3Path, eTag string, opts CopyOptions) error {
 	copyParams := &s3.CopyObjectInput{
 		Bucket:     aws.String(dst.Bucket),
 		Key:        aws.String(dst.Key),
 		CopySource: aws.String(src.Path()),
+
+		RequestPayer:              c.opts.requestPayer(opts.CallerPays),
+		ExpectedBucketOwner:       c.opts.bucketOwner(),
+		ExpectedSourceBucketOwner: c.opts.bucketOwner(),
+		StorageClass:              c.opts.storageClassValue(),
+	}
+	if opts.ReplaceMetadata {
+		copyParams.MetadataDirective = aws.String(s3.MetadataDirectiveReplace)
+		copyParams.Metadata = aws.StringMap(opts.Metadata)
+		if opts.ContentType != "" {
+			copyParams.ContentType = aws.String(opts.ContentType)
+		}
 	}
-	if callerPays {
-		copyParams.RequestPayer = aws.String(payerRequester)
+
+	copyParams.ServerSideEncryption, copyParams.SSEKMSKeyId = opts.Encryption.serverSide()
+	copyParams.SSECustomerAlgorithm, copyParams.SSECustomerKey, copyParams.SSECustomerKeyMD5 = opts.Encryption.customer()
+	copyParams.CopySourceSSECustomerAlgorithm,
+		copyParams.CopySourceSSECustomerKey,
+		copyParams.CopySourceSSECustomerKeyMD5 = opts.SourceEncryption.customer()
+	if eTag != "" {
+		// the source must not change since its HEAD, ErrPreconditionFailed is returned otherwise.
+		copyParams.CopySourceIfMatch = aws.String(eTag)
 	}
 
-	copyResult, err := c.awsS3.CopyObject(copyParams)
+	copyResult, err := c.awsS3.CopyObjectWithContext(ctx, copyParams)
 	if err != nil {
-		return err
+		return newError(copyErrorPath(src, dst, err), err)
 	}
 
-	return validateCopyResult(validateEtag, eTag, copyResult)
+	return validateCopyResult(dst, opts.ValidateETag, eTag, copyResult)
 }
 
-func validateCopyResult(validateEtag bool, eTag string, copyResult *s3.CopyObjectOutput) error {
+// validateCopyResult compares an ETag of the copy result with the source one if required.
+func validateCopyResult(dst S3Path, validateEtag bool, eTag string, copyResult *s3.CopyObjectOutput) error {
 	if validateEtag {
 		if copyResult == nil ||
 			copyResult.CopyObjectResult == nil ||
@@ -194,77 +249,46 @@ func validateCopyResult(validateEtag bool, eTag string, copyResult *s3.CopyObjec
 		}
 
 		if eTag != *copyResult.CopyObjectResult.ETag {
-			return fmt.Errorf("copy operation result ETag %+v and the source one is %+v",
-				*copyResult.CopyObjectResult.ETag,
-				eTag,
-			)
+			return newETagMismatchError(dst, *copyResult.CopyObjectResult.ETag, eTag)
 		}
 	}
 
 	return nil
 }
 
-// IsSrcNewer returns true if source exist and newer thad destination, or when destination does not exist.
-func (c *s3Client) IsSrcNewer(src, dst S3Path, callerPays bool) (bool, error) {
-	headParams := &s3.HeadObjectInput{
-		Bucket: aws.String(src.Bucket),
-		Key:    aws.String(src.Key),
-	}
-	if callerPays {
-		headParams.RequestPayer = aws.String(payerRequester)
-	}
-
-	headSrc, err := c.awsS3.HeadObject(headParams)
+// IsSrcNewerWithContext returns true if source exist and newer thad destination, or when destination does not exist.
+func (c *s3Client) IsSrcNewerWithContext(ctx context.Context, src, dst S3Path, callerPays bool) (bool, error) {
+	srcInfo, err := c.StatWithContext(ctx, src, callerPays)
 	if err != nil {
-		return false, fmt.Errorf("can not query source head %v : %w", src, err)
+		return false, fmt.Errorf("can not query source head: %w", err)
 	}
 
 	// check destination.
-	headParams = &s3.HeadObjectInput{
-		Bucket: aws.String(dst.Bucket),
-		Key:    aws.String(dst.Key),
-	}
-
-	headDst, err := c.awsS3.HeadObject(headParams)
+	dstInfo, err := c.StatWithContext(ctx, dst, callerPays)
 	if err != nil {
 		var awsErr awserr.Error
 		if ok := errors.As(err, &awsErr); ok && awsErr.Code() == awsErrNotFound {
 			return true, nil
 		}
 
-		return false, fmt.Errorf("error querying head %v : %w", dst, err)
+		return false, fmt.Errorf("error querying head: %w", err)
 	}
 
-	return headSrc.LastModified.After(*headDst.LastModified), nil
-}
-
-// GetPresignedURL returns an S3 presigned URL for the given key.
-func (c *s3Client) GetPresignedURL(path S3Path, duration time.Duration) (string, error) {
-	req, _ := c.awsS3.GetObjectRequest(&s3.GetObjectInput{
-		Bucket: aws.String(path.Bucket),
-		Key:    aws.String(path.Key),
-	})
-
-	return req.Presign(duration)
+	return srcInfo.LastModified.After(dstInfo.LastModified), nil
 }
 
-// GetPresignedURL returns an S3 presigned URL for the given key.
-func (c *s3Client) GetETag(path S3Path) (string, error) {
-	headInput := &s3.HeadObjectInput{
-		Bucket: aws.String(path.Bucket),
-		Key:    aws.String(path.Key),
-	}
-
-	result, err := c.awsS3.HeadObject(headInput)
+// GetETagWithContext returns an ETag of the object.
+func (c *s3Client) GetETagWithContext(ctx context.Context, path S3Path) (string, error) {
+	info, err := c.StatWithContext(ctx, path, false)
 	if err != nil {
-		return "", fmt.Errorf("error querying head for ETag %v : %w", path, err)
+		return "", fmt.Errorf("error querying head for ETag: %w", err)
 	}
 
-	if result.ETag == nil {
+	if info.ETag == "" {
 		return "", fmt.Errorf("head returned a nil ETag %v", path)
 	}
 
-	return *result.ETag, nil
+	return info.ETag, nil
 }
 
 // completedParts a utility type used to sort completed parts in a multipart upload.
@@ -274,99 +298,134 @@ func (a completedParts) Len() int           { return len(a) }
 func (a completedParts) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a completedParts) Less(i, j int) bool { return *a[i].PartNumber < *a[j].PartNumber }
 
-// nolint:funlen
-func (c *s3Client) copyMultipartInt(
-	src, dst S3Path,
-	srcSize int64,
-	chunkSize int64,
-	callerPays bool) error {
-	multipartParams := &s3.CreateMultipartUploadInput{
-		Bucket: aws.String(dst.Bucket),
-		Key:    aws.String(dst.Key),
-	}
-	upload, err := c.awsS3.CreateMultipartUpload(multipartParams)
-	if err != nil {
-		return err
-	}
-
-	partsNumber := srcSize/chunkSize + 1
-	resCh := make(chan *s3.CompletedPart, partsNumber)
-	defer close(resCh)
-	errCh := make(chan error, partsNumber)
-	defer close(errCh)
-	parts := 0
-
-	var bytePosition int64
-	var wg sync.WaitGroup
-	for i := int64(1); bytePosition < srcSize; i++ {
-		endRange := bytePosition + chunkSize - 1
-		if endRange >= srcSize {
-			endRange = srcSize - 1
+// createCopyUpload initiates a multipart upload of a copy and returns its ID.
+func (c *s3Client) createCopyUpload(ctx context.Context, dst S3Path, srcInfo ObjectInfo, opts CopyOptions) (string, error) {
+	params := &s3.CreateMultipartUploadInput{
+		Bucket:              aws.String(dst.Bucket),
+		Key:                 aws.String(dst.Key),
+		RequestPayer:        c.opts.requestPayer(opts.CallerPays),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+		StorageClass:        c.opts.storageClassValue(),
+	}
+	// unlike CopyObject a multipart upload does not copy the source metadata.
+	if opts.ReplaceMetadata {
+		params.Metadata = aws.StringMap(opts.Metadata)
+		if opts.ContentType != "" {
+			params.ContentType = aws.String(opts.ContentType)
 		}
-
-		partRange := fmt.Sprintf("bytes=%v-%v", bytePosition, endRange)
-
-		partCopyParam := &s3.UploadPartCopyInput{
-			Bucket:          aws.String(dst.Bucket),
-			Key:             aws.String(dst.Key),
-			CopySource:      aws.String(src.Path()),
-			UploadId:        upload.UploadId,
-			CopySourceRange: aws.String(partRange),
-			PartNumber:      aws.Int64(i),
+	} else {
+		params.Metadata = aws.StringMap(srcInfo.Metadata)
+		if srcInfo.ContentType != "" {
+			params.ContentType = aws.String(srcInfo.ContentType)
 		}
-		if callerPays {
-			partCopyParam.RequestPayer = aws.String(payerRequester)
+		if srcInfo.ContentEncoding != "" {
+			params.ContentEncoding = aws.String(srcInfo.ContentEncoding)
 		}
+		if srcInfo.CacheControl != "" {
+			params.CacheControl = aws.String(srcInfo.CacheControl)
+		}
+	}
 
-		wg.Add(1)
-		go func(wg *sync.WaitGroup, param *s3.UploadPartCopyInput) {
-			res, err := c.awsS3.UploadPartCopy(param)
-			if err != nil {
-				errCh <- fmt.Errorf("failed to copy part: %w", err)
-			} else {
-				resCh <- &s3.CompletedPart{
-					ETag:       res.CopyPartResult.ETag,
-					PartNumber: param.PartNumber,
-				}
-			}
-			wg.Done()
-		}(&wg, partCopyParam)
+	params.ServerSideEncryption, params.SSEKMSKeyId = opts.Encryption.serverSide()
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = opts.Encryption.customer()
 
-		bytePosition += chunkSize
-		parts++
+	upload, err := c.awsS3.CreateMultipartUploadWithContext(ctx, params)
+	if err != nil {
+		return "", newError(dst, err)
 	}
 
-	// wait until all parts are uploaded.
-	wg.Wait()
+	return aws.StringValue(upload.UploadId), nil
+}
 
-	errStr := ""
-	if len(errCh) > 0 {
-		errStr = "multipart upload error(s):"
-		for len(errCh) > 0 {
-			errStr += fmt.Sprintf(" [%+v]", <-errCh)
-		}
+// uploadPartCopy copies the byte range of the source as a part of a multipart upload and returns its ETag.
+// The source must still have the eTag if it is set, ErrPreconditionFailed is returned otherwise.
+func (c *s3Client) uploadPartCopy(
+	ctx context.Context,
+	src, dst S3Path,
+	uploadID string,
+	partNumber int64,
+	byteRange *ByteRange,
+	eTag string,
+	opts CopyOptions,
+) (string, error) {
+	params := &s3.UploadPartCopyInput{
+		Bucket:          aws.String(dst.Bucket),
+		Key:             aws.String(dst.Key),
+		CopySource:      aws.String(src.Path()),
+		UploadId:        aws.String(uploadID),
+		CopySourceRange: aws.String(byteRange.String()),
+		PartNumber:      aws.Int64(partNumber),
+
+		RequestPayer:              c.opts.requestPayer(opts.CallerPays),
+		ExpectedBucketOwner:       c.opts.bucketOwner(),
+		ExpectedSourceBucketOwner: c.opts.bucketOwner(),
 	}
-	if errStr != "" {
-		return errors.New(errStr)
+	if eTag != "" {
+		params.CopySourceIfMatch = aws.String(eTag)
 	}
 
-	partsArr := make(completedParts, parts)
-	for i := 0; i < parts; i++ {
-		partsArr[i] = <-resCh
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = opts.Encryption.customer()
+	params.CopySourceSSECustomerAlgorithm,
+		params.CopySourceSSECustomerKey,
+		params.CopySourceSSECustomerKeyMD5 = opts.SourceEncryption.customer()
+
+	res, err := c.awsS3.UploadPartCopyWithContext(ctx, params)
+	if err != nil {
+		return "", newError(partCopyErrorPath(src, dst, err), err)
 	}
-	sort.Sort(partsArr)
 
-	completedUpload := &s3.CompletedMultipartUpload{
-		Parts: partsArr,
+	if res.CopyPartResult == nil {
+		return "", fmt.Errorf("copy result of part %v is empty", partNumber)
 	}
 
-	completeParam := &s3.CompleteMultipartUploadInput{
-		Bucket:          aws.String(dst.Bucket),
-		Key:             aws.String(dst.Key),
-		UploadId:        upload.UploadId,
-		MultipartUpload: completedUpload,
+	return aws.StringValue(res.CopyPartResult.ETag), nil
+}
+
+// completeCopyUpload completes a multipart upload of a copy from the parts sorted by their numbers
+// and returns the result ETag.
+func (c *s3Client) completeCopyUpload(
+	ctx context.Context,
+	dst S3Path,
+	uploadID string,
+	parts []uploadedPart,
+	opts CopyOptions,
+) (string, error) {
+	partsArr := make(completedParts, len(parts))
+	for i, part := range parts {
+		partsArr[i] = &s3.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int64(part.PartNumber),
+		}
+	}
+
+	params := &s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String(dst.Bucket),
+		Key:      aws.String(dst.Key),
+		UploadId: aws.String(uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{
+			Parts: partsArr,
+		},
+
+		RequestPayer:        c.opts.requestPayer(opts.CallerPays),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = opts.Encryption.customer()
+
+	res, err := c.awsS3.CompleteMultipartUploadWithContext(ctx, params)
+	if err != nil {
+		return "", newError(dst, err)
+	}
+
+	return aws.StringValue(res.ETag), nil
+}
+
+// abortFailedUpload aborts a multipart upload that failed with the err to not leave its parts behind
+// and returns the err extended with an abort error if any.
+// A background context is used since the upload might fail because of its context cancellation.
+func (c *s3Client) abortFailedUpload(path S3Path, uploadID string, err error) error {
+	if abortErr := c.abortMultipartUpload(context.Background(), path, uploadID); abortErr != nil {
+		return fmt.Errorf("%w; %v", err, abortErr)
 	}
-	_, err = c.awsS3.CompleteMultipartUpload(completeParam)
 
 	return err
 }
@@ -0,0 +1,903 @@
+package s3client
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" //nolint:gosec // MD5 is required to compute S3 ETags.
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	fakeListPageSize = 1000
+
+	awsErrInvalidRange  = "InvalidRange"
+	fakeNullVersionID   = "null" // a version of objects of a bucket without versioning enabled.
+	fakePresignedURLFmt = "https://%s.s3.fake.local/%s?X-Amz-Expires=%d"
+)
+
+// fakeObject is an object stored by FakeClient.
+type fakeObject struct {
+	data         []byte
+	eTag         string
+	lastModified time.Time
+	contentType  string
+	metadata     map[string]string
+	tags         map[string]string
+	storageClass string
+}
+
+// fakeBucket is a bucket stored by FakeClient.
+type fakeBucket struct {
+	objects        map[string]*fakeObject
+	requesterPays  bool
+	lifecycleRules []LifecycleRule
+	policy         string
+}
+
+// FakeClient is an in-memory S3Client implementation to be used in tests instead of a real S3 connection.
+// It returns AWS errors with the same codes a real S3 returns, so NotFound handling is the same.
+// Buckets behave as ones without versioning: every object has a single "null" version.
+type FakeClient struct {
+	backgroundClient
+
+	mu      sync.RWMutex
+	buckets map[string]*fakeBucket
+	opts    clientOptions
+}
+
+// NewFakeClient returns an empty in-memory S3 client. Requester pays and storage class options are honored.
+func NewFakeClient(opts ...ClientOption) *FakeClient {
+	c := &FakeClient{
+		buckets: make(map[string]*fakeBucket),
+		opts:    newClientOptions(opts...),
+	}
+	c.backgroundClient = backgroundClient{c}
+
+	return c
+}
+
+// NewFakeBucketClient returns a bucket's client of an in-memory S3 client creating the bucket.
+func NewFakeBucketClient(bucket string) (BucketClient, *FakeClient) {
+	c := NewFakeClient()
+	c.createBucket(bucket)
+
+	return NewBucketClientWithClient(c, bucket), c
+}
+
+// SetRequesterPays marks a bucket as a requester pays one: any request that is not flagged
+// as a paid by the caller is denied.
+func (c *FakeClient) SetRequesterPays(bucket string, enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.createBucketLocked(bucket).requesterPays = enabled
+}
+
+// SetObject stores an object with the last modification time provided creating its bucket if required.
+func (c *FakeClient) SetObject(path S3Path, data []byte, lastModified time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.createBucketLocked(path.Bucket).objects[path.Key] = newFakeObject(data, lastModified)
+}
+
+// CreateBucketWithContext creates a bucket if it does not exist.
+func (c *FakeClient) CreateBucketWithContext(ctx context.Context, bucket string) (string, error) {
+	return c.CreateBucketWithOptionsWithContext(ctx, bucket, CreateBucketOptions{})
+}
+
+// CreateBucketWithOptionsWithContext creates a bucket if it does not exist.
+// The options are ignored as fake buckets have neither regions nor versioning.
+func (c *FakeClient) CreateBucketWithOptionsWithContext(
+	ctx context.Context,
+	bucket string,
+	_ CreateBucketOptions,
+) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	c.createBucket(bucket)
+
+	return "/" + bucket, nil
+}
+
+// BucketExistsWithContext returns true if the bucket exists.
+func (c *FakeClient) BucketExistsWithContext(ctx context.Context, bucket string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.buckets[bucket]
+
+	return ok, nil
+}
+
+// DeleteBucketWithContext deletes the bucket, a non-empty one is deleted only if force is set.
+func (c *FakeClient) DeleteBucketWithContext(ctx context.Context, bucket string, force bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := S3Path{Bucket: bucket}
+
+	b, err := c.bucketLocked(path, true)
+	if err != nil {
+		return err
+	}
+
+	if len(b.objects) > 0 && !force {
+		return fakeError(awsErrBucketNotEmpty, http.StatusConflict, path)
+	}
+
+	delete(c.buckets, bucket)
+
+	return nil
+}
+
+// GetLifecycleRulesWithContext returns lifecycle rules of the bucket.
+func (c *FakeClient) GetLifecycleRulesWithContext(ctx context.Context, bucket string) ([]LifecycleRule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	b, err := c.bucketLocked(S3Path{Bucket: bucket}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]LifecycleRule{}, b.lifecycleRules...), nil
+}
+
+// SetLifecycleRulesWithContext replaces lifecycle rules of the bucket.
+func (c *FakeClient) SetLifecycleRulesWithContext(ctx context.Context, bucket string, rules []LifecycleRule) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := c.bucketLocked(S3Path{Bucket: bucket}, true)
+	if err != nil {
+		return err
+	}
+
+	b.lifecycleRules = append([]LifecycleRule{}, rules...)
+
+	return nil
+}
+
+// GetBucketPolicyWithContext returns a JSON policy of the bucket or an empty string if none is set.
+func (c *FakeClient) GetBucketPolicyWithContext(ctx context.Context, bucket string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	b, err := c.bucketLocked(S3Path{Bucket: bucket}, true)
+	if err != nil {
+		return "", err
+	}
+
+	return b.policy, nil
+}
+
+// SetBucketPolicyWithContext replaces a JSON policy of the bucket.
+func (c *FakeClient) SetBucketPolicyWithContext(ctx context.Context, bucket, policy string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := c.bucketLocked(S3Path{Bucket: bucket}, true)
+	if err != nil {
+		return err
+	}
+
+	b.policy = policy
+
+	return nil
+}
+
+// ExistsWithContext returns true if S3 object exists.
+func (c *FakeClient) ExistsWithContext(ctx context.Context, path S3Path) (bool, error) {
+	_, err := c.getObject(ctx, path, false, awsErrNotFound)
+	if err != nil {
+		if isFakeErrorCode(err, awsErrNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetSizeWithContext returns a size in bytes of the object.
+func (c *FakeClient) GetSizeWithContext(ctx context.Context, path S3Path, callerPays bool) (int64, error) {
+	obj, err := c.getObject(ctx, path, callerPays, awsErrNotFound)
+	if err != nil {
+		return -1, err
+	}
+
+	return int64(len(obj.data)), nil
+}
+
+// StatWithContext returns all the object attributes.
+func (c *FakeClient) StatWithContext(ctx context.Context, path S3Path, callerPays bool) (ObjectInfo, error) {
+	obj, err := c.getObject(ctx, path, callerPays, awsErrNotFound)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	return obj.info(path), nil
+}
+
+// GetTagsWithContext returns tags of the object.
+func (c *FakeClient) GetTagsWithContext(ctx context.Context, path S3Path) (map[string]string, error) {
+	obj, err := c.getObject(ctx, path, false, awsErrNoSuchKey)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tags: %w", err)
+	}
+
+	tags := copyStringMap(obj.tags)
+	if tags == nil {
+		tags = map[string]string{}
+	}
+
+	return tags, nil
+}
+
+// SetTagsWithContext replaces tags of the object by the ones provided.
+func (c *FakeClient) SetTagsWithContext(ctx context.Context, path S3Path, tags map[string]string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := c.bucketLocked(path, false)
+	if err != nil {
+		return fmt.Errorf("error setting tags: %w", err)
+	}
+
+	obj, ok := b.objects[path.Key]
+	if !ok {
+		return fmt.Errorf("error setting tags: %w", fakeError(awsErrNoSuchKey, http.StatusNotFound, path))
+	}
+	obj.tags = copyStringMap(tags)
+
+	return nil
+}
+
+// GetObjectWithContext returns an S3 object in a byte array view.
+func (c *FakeClient) GetObjectWithContext(ctx context.Context, path S3Path, callerPays bool) ([]byte, error) {
+	obj, err := c.getObject(ctx, path, callerPays, awsErrNoSuchKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{}, obj.data...), nil
+}
+
+// GetObjectStreamWithContext returns a reader of an S3 object content. A nil byteRange means the whole object.
+func (c *FakeClient) GetObjectStreamWithContext(
+	ctx context.Context,
+	path S3Path,
+	callerPays bool,
+	byteRange *ByteRange,
+) (io.ReadCloser, error) {
+	return c.getObjectRange(ctx, path, callerPays, byteRange, "")
+}
+
+// getObjectRange returns a reader of a byte range of an object. ErrPreconditionFailed is returned
+// if ifMatch is set and the object ETag differs.
+func (c *FakeClient) getObjectRange(
+	ctx context.Context,
+	path S3Path,
+	callerPays bool,
+	byteRange *ByteRange,
+	ifMatch string,
+) (io.ReadCloser, error) {
+	obj, err := c.getObject(ctx, path, callerPays, awsErrNoSuchKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if ifMatch != "" && ifMatch != obj.eTag {
+		return nil, fakeError(awsErrPreconditionFailed, http.StatusPreconditionFailed, path)
+	}
+
+	data := obj.data
+	if byteRange != nil {
+		start, end, ok := byteRange.bounds(int64(len(data)))
+		if !ok {
+			return nil, fakeError(awsErrInvalidRange, http.StatusRequestedRangeNotSatisfiable, path)
+		}
+		data = data[start : end+1]
+	}
+
+	return io.NopCloser(bytes.NewReader(append([]byte{}, data...))), nil
+}
+
+// DownloadFileWithContext downloads an object to a local file by byte ranges the same way the real clients do.
+func (c *FakeClient) DownloadFileWithContext(ctx context.Context, path S3Path, localPath string, opts DownloadFileOptions) error {
+	return downloadFile(ctx, c, path, localPath, opts)
+}
+
+// GetObjectIfChangedWithContext returns an object content and attributes if the conditions are met
+// or ErrNotModified if the object has not been changed.
+func (c *FakeClient) GetObjectIfChangedWithContext(
+	ctx context.Context,
+	path S3Path,
+	callerPays bool,
+	cond GetConditions,
+) ([]byte, ObjectInfo, error) {
+	obj, err := c.getObject(ctx, path, callerPays, awsErrNoSuchKey)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	if cond.isNotModified(obj.eTag, obj.lastModified) {
+		return nil, ObjectInfo{}, fakeError(awsErrNotModified, http.StatusNotModified, path)
+	}
+
+	return append([]byte{}, obj.data...), obj.info(path), nil
+}
+
+// PutObjectWithContext stores a body to the S3 path.
+func (c *FakeClient) PutObjectWithContext(ctx context.Context, path S3Path, body io.Reader) error {
+	return c.UploadWithContext(ctx, path, body, UploadOptions{})
+}
+
+// UploadWithContext stores a body to the S3 path.
+func (c *FakeClient) UploadWithContext(ctx context.Context, path S3Path, body io.Reader, opts UploadOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	obj := newFakeObject(data, time.Now())
+	obj.contentType = opts.ContentType
+	obj.metadata = copyStringMap(opts.Metadata)
+
+	return c.putObject(path, obj, opts.IfAbsent)
+}
+
+// UploadFileWithContext uploads a local file at once, so no checkpoint file is used.
+func (c *FakeClient) UploadFileWithContext(ctx context.Context, localPath string, path S3Path, opts UploadFileOptions) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return c.UploadWithContext(ctx, path, file, opts.UploadOptions)
+}
+
+// DeleteWithContext deletes an S3 object, a missing object is not an error.
+func (c *FakeClient) DeleteWithContext(ctx context.Context, path S3Path) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := c.bucketLocked(path, false)
+	if err != nil {
+		return err
+	}
+
+	if isFakeVersion(path) {
+		delete(b.objects, path.Key)
+	}
+
+	return nil
+}
+
+// DeleteManyWithContext deletes objects and returns objects that were not deleted.
+func (c *FakeClient) DeleteManyWithContext(ctx context.Context, objs []S3Path) ([]DeleteFailure, error) {
+	failures := []DeleteFailure{}
+	for _, obj := range objs {
+		if err := c.DeleteWithContext(ctx, obj); err != nil {
+			failure := DeleteFailure{
+				Path:    obj,
+				Message: err.Error(),
+			}
+			var awsErr awserr.Error
+			if ok := errors.As(err, &awsErr); ok {
+				failure.Code = awsErr.Code()
+				failure.Message = awsErr.Message()
+			}
+			failures = append(failures, failure)
+		}
+	}
+
+	return failures, nil
+}
+
+// DeletePrefixWithContext deletes all objects which keys start with the prefix.Key
+// and returns a number of objects deleted. An empty prefix.Key is an error.
+func (c *FakeClient) DeletePrefixWithContext(ctx context.Context, prefix S3Path) (int, error) {
+	if err := checkDeletePrefix(prefix); err != nil {
+		return 0, err
+	}
+
+	objects, err := c.ListWithContext(ctx, prefix, "").All()
+	if err != nil {
+		return 0, err
+	}
+
+	paths := make([]S3Path, len(objects))
+	for i, obj := range objects {
+		paths[i] = obj.Path
+	}
+
+	failures, err := c.DeleteManyWithContext(ctx, paths)
+	if err != nil {
+		return len(paths) - len(failures), err
+	}
+
+	if len(failures) > 0 {
+		return len(paths) - len(failures), fmt.Errorf("failed to delete %v object(s), first one: %w", len(failures), failures[0])
+	}
+
+	return len(paths), nil
+}
+
+// CopyWithContext copies source to destination.
+func (c *FakeClient) CopyWithContext(ctx context.Context, src, dst S3Path, validateEtag, callerPays bool) error {
+	return c.CopyObjectWithContext(ctx, src, dst, CopyOptions{
+		ValidateETag: validateEtag,
+		CallerPays:   callerPays,
+	})
+}
+
+// CopyObjectWithContext copies source to destination, the copy keeps the source ETag.
+// The source is read from opts.SourceClient if set, so fake clients can emulate buckets of different regions.
+func (c *FakeClient) CopyObjectWithContext(ctx context.Context, src, dst S3Path, opts CopyOptions) error {
+	obj, err := c.copySource(ctx, src, opts)
+	if err != nil {
+		return err
+	}
+
+	copied := &fakeObject{
+		data:         obj.data,
+		eTag:         obj.eTag,
+		lastModified: time.Now(),
+		contentType:  obj.contentType,
+		metadata:     copyStringMap(obj.metadata),
+		tags:         copyStringMap(obj.tags),
+	}
+	if opts.ReplaceMetadata {
+		copied.metadata = copyStringMap(opts.Metadata)
+		if opts.ContentType != "" {
+			copied.contentType = opts.ContentType
+		}
+	}
+
+	return c.putObject(dst, copied, false)
+}
+
+// copySource returns an object to copy from the client or from the source client of the options.
+func (c *FakeClient) copySource(ctx context.Context, src S3Path, opts CopyOptions) (*fakeObject, error) {
+	switch source := opts.SourceClient.(type) {
+	case nil:
+		return c.getObject(ctx, src, opts.CallerPays, awsErrNoSuchKey)
+	case *FakeClient:
+		return source.getObject(ctx, src, opts.CallerPays, awsErrNoSuchKey)
+	default:
+		info, err := source.StatWithContext(ctx, src, opts.CallerPays)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := source.GetObjectWithContext(ctx, src, opts.CallerPays)
+		if err != nil {
+			return nil, err
+		}
+
+		tags, err := source.GetTagsWithContext(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+
+		return &fakeObject{
+			data:        data,
+			eTag:        info.ETag,
+			contentType: info.ContentType,
+			metadata:    info.Metadata,
+			tags:        tags,
+		}, nil
+	}
+}
+
+// IsSrcNewerWithContext returns true if source exist and newer than destination, or when destination does not exist.
+func (c *FakeClient) IsSrcNewerWithContext(ctx context.Context, src, dst S3Path, callerPays bool) (bool, error) {
+	srcObj, err := c.getObject(ctx, src, callerPays, awsErrNotFound)
+	if err != nil {
+		return false, fmt.Errorf("can not query source head: %w", err)
+	}
+
+	dstObj, err := c.getObject(ctx, dst, callerPays, awsErrNotFound)
+	if err != nil {
+		if isFakeErrorCode(err, awsErrNotFound) {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("error querying head: %w", err)
+	}
+
+	return srcObj.lastModified.After(dstObj.lastModified), nil
+}
+
+// SyncWithContext makes objects of the dst prefix the same as the ones of the src prefix.
+func (c *FakeClient) SyncWithContext(ctx context.Context, src, dst S3Path, opts SyncOptions) (*SyncReport, error) {
+	return syncPrefix(ctx, c, src, dst, opts)
+}
+
+// SyncFromDirWithContext makes objects of the dst prefix the same as files of the local directory.
+func (c *FakeClient) SyncFromDirWithContext(ctx context.Context, localDir string, dst S3Path, opts SyncOptions) (*SyncReport, error) {
+	return syncFromDir(ctx, c, localDir, dst, opts)
+}
+
+// GetPresignedURLWithContext returns a fake URL of the object, it is not validated.
+func (c *FakeClient) GetPresignedURLWithContext(ctx context.Context, path S3Path, duration time.Duration) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	presignedURL := fmt.Sprintf(fakePresignedURLFmt, path.Bucket, url.PathEscape(path.Key), int64(duration.Seconds()))
+	if path.VersionID != "" {
+		presignedURL += "&versionId=" + url.QueryEscape(path.VersionID)
+	}
+
+	return presignedURL, nil
+}
+
+// GetPresignedPutURLWithContext returns a fake URL of the object, it is not validated.
+func (c *FakeClient) GetPresignedPutURLWithContext(
+	ctx context.Context,
+	path S3Path,
+	duration time.Duration,
+	contentType, contentMD5 string,
+) (string, error) {
+	return c.GetPresignedURLWithContext(ctx, path, duration)
+}
+
+// GetPresignedDeleteURLWithContext returns a fake URL of the object, it is not validated.
+func (c *FakeClient) GetPresignedDeleteURLWithContext(ctx context.Context, path S3Path, duration time.Duration) (string, error) {
+	return c.GetPresignedURLWithContext(ctx, path, duration)
+}
+
+// GetPresignedPostWithContext returns a fake URL of the bucket and a key field only.
+func (c *FakeClient) GetPresignedPostWithContext(
+	ctx context.Context,
+	path S3Path,
+	duration time.Duration,
+	opts PostPolicyOptions,
+) (*PresignedPost, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	key := path.Key
+	if opts.KeyPrefix {
+		key += postPolicyFilenameVar
+	}
+
+	return &PresignedPost{
+		URL: fmt.Sprintf(fakePresignedURLFmt, path.Bucket, "", int64(duration.Seconds())),
+		Fields: map[string]string{
+			"key": key,
+		},
+	}, nil
+}
+
+// GetETagWithContext returns an ETag of the object.
+func (c *FakeClient) GetETagWithContext(ctx context.Context, path S3Path) (string, error) {
+	obj, err := c.getObject(ctx, path, false, awsErrNotFound)
+	if err != nil {
+		return "", fmt.Errorf("error querying head for ETag: %w", err)
+	}
+
+	return obj.eTag, nil
+}
+
+// ListWithContext returns an iterator over objects of the prefix.Bucket which keys start with the prefix.Key.
+func (c *FakeClient) ListWithContext(ctx context.Context, prefix S3Path, delimiter string) *ObjectIterator {
+	return newObjectIterator(ctx, func(ctx context.Context, token string) ([]ObjectInfo, string, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, "", err
+		}
+
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+
+		b, err := c.bucketLocked(prefix, false)
+		if err != nil {
+			return nil, "", err
+		}
+
+		// every common prefix and object is listed once, in the keys order.
+		entries := map[string]ObjectInfo{}
+		for key, obj := range b.objects {
+			if !strings.HasPrefix(key, prefix.Key) {
+				continue
+			}
+
+			if delimiter != "" {
+				if idx := strings.Index(key[len(prefix.Key):], delimiter); idx != -1 {
+					commonPrefix := key[:len(prefix.Key)+idx+len(delimiter)]
+					entries[commonPrefix] = ObjectInfo{
+						Path: S3Path{
+							Bucket: prefix.Bucket,
+							Key:    commonPrefix,
+						},
+						IsPrefix: true,
+					}
+
+					continue
+				}
+			}
+
+			entries[key] = obj.info(S3Path{
+				Bucket: prefix.Bucket,
+				Key:    key,
+			})
+		}
+
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			if key > token {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		nextToken := ""
+		if len(keys) > fakeListPageSize {
+			keys = keys[:fakeListPageSize]
+			nextToken = keys[len(keys)-1]
+		}
+
+		page := make([]ObjectInfo, len(keys))
+		for i, key := range keys {
+			page[i] = entries[key]
+		}
+
+		return page, nextToken, nil
+	})
+}
+
+// ListVersionsWithContext returns an iterator over the objects of the prefix, each one has a single "null" version.
+func (c *FakeClient) ListVersionsWithContext(ctx context.Context, prefix S3Path) *ObjectIterator {
+	objects := c.ListWithContext(ctx, prefix, "")
+
+	return newObjectIterator(ctx, func(ctx context.Context, token string) ([]ObjectInfo, string, error) {
+		page, err := objects.All()
+		if err != nil {
+			return nil, "", err
+		}
+
+		for i := range page {
+			page[i].Path.VersionID = fakeNullVersionID
+			page[i].IsLatest = true
+		}
+
+		return page, "", nil
+	})
+}
+
+// ListIncompleteUploadsWithContext returns no uploads since the fake client completes uploads immediately.
+func (c *FakeClient) ListIncompleteUploadsWithContext(ctx context.Context, bucket, prefix string) ([]IncompleteUpload, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if _, err := c.bucketLocked(S3Path{Bucket: bucket}, false); err != nil {
+		return nil, err
+	}
+
+	return []IncompleteUpload{}, nil
+}
+
+// AbortUploadWithContext always fails since the fake client has no incomplete uploads.
+func (c *FakeClient) AbortUploadWithContext(ctx context.Context, upload IncompleteUpload) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return fakeError(awsErrNoSuchUpload, http.StatusNotFound, upload.Path)
+}
+
+// AbortStaleUploadsWithContext aborts nothing since the fake client has no incomplete uploads.
+func (c *FakeClient) AbortStaleUploadsWithContext(ctx context.Context, bucket string, olderThan time.Duration) (int, error) {
+	_, err := c.ListIncompleteUploadsWithContext(ctx, bucket, "")
+
+	return 0, err
+}
+
+// createBucket creates a bucket if it does not exist.
+func (c *FakeClient) createBucket(bucket string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.createBucketLocked(bucket)
+}
+
+// createBucketLocked creates a bucket if it does not exist and returns it. The caller holds the lock.
+func (c *FakeClient) createBucketLocked(bucket string) *fakeBucket {
+	b, ok := c.buckets[bucket]
+	if !ok {
+		b = &fakeBucket{
+			objects: make(map[string]*fakeObject),
+		}
+		c.buckets[bucket] = b
+	}
+
+	return b
+}
+
+// bucketLocked returns a bucket of the path checking the requester pays flag. The caller holds the lock.
+func (c *FakeClient) bucketLocked(path S3Path, callerPays bool) (*fakeBucket, error) {
+	b, ok := c.buckets[path.Bucket]
+	if !ok {
+		return nil, fakeError(awsErrNoSuchBucket, http.StatusNotFound, path)
+	}
+
+	if b.requesterPays && !callerPays && !c.opts.requesterPays {
+		return nil, fakeError(awsErrAccessDenied, http.StatusForbidden, path)
+	}
+
+	return b, nil
+}
+
+// getObject returns an object or an error with the notFoundCode if it does not exist.
+// HEAD requests of a real S3 fail with NotFound while GET ones fail with NoSuchKey.
+func (c *FakeClient) getObject(ctx context.Context, path S3Path, callerPays bool, notFoundCode string) (*fakeObject, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	b, err := c.bucketLocked(path, callerPays)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, ok := b.objects[path.Key]
+	if !ok {
+		return nil, fakeError(notFoundCode, http.StatusNotFound, path)
+	}
+
+	if !isFakeVersion(path) {
+		return nil, fakeError(awsErrNoSuchVersion, http.StatusNotFound, path)
+	}
+
+	return obj, nil
+}
+
+// putObject stores an object to an existing bucket, an existing object is not replaced if ifAbsent is set.
+func (c *FakeClient) putObject(path S3Path, obj *fakeObject, ifAbsent bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := c.bucketLocked(path, false)
+	if err != nil {
+		return err
+	}
+
+	if _, exists := b.objects[path.Key]; exists && ifAbsent {
+		return fakeError(awsErrPreconditionFailed, http.StatusPreconditionFailed, path)
+	}
+
+	if obj.storageClass == "" {
+		obj.storageClass = c.opts.storageClass
+	}
+	b.objects[path.Key] = obj
+
+	return nil
+}
+
+// newFakeObject returns an object with a single part ETag.
+func newFakeObject(data []byte, lastModified time.Time) *fakeObject {
+	sum := md5.Sum(data) //nolint:gosec // MD5 is required to compute S3 ETags.
+
+	return &fakeObject{
+		data:         append([]byte{}, data...),
+		eTag:         fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:])),
+		lastModified: lastModified,
+	}
+}
+
+// info returns attributes of the object.
+func (o *fakeObject) info(path S3Path) ObjectInfo {
+	storageClass := o.storageClass
+	if storageClass == "" {
+		storageClass = s3.StorageClassStandard
+	}
+
+	return ObjectInfo{
+		Path:         path,
+		Size:         int64(len(o.data)),
+		ETag:         o.eTag,
+		LastModified: o.lastModified,
+		StorageClass: storageClass,
+		ContentType:  o.contentType,
+		Metadata:     copyStringMap(o.metadata),
+	}
+}
+
+// copyStringMap returns a copy of the map, nil for an empty one.
+func copyStringMap(m map[string]string) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+
+	res := make(map[string]string, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+
+	return res
+}
+
+// fakeError returns an AWS request failure with the code and status provided.
+func fakeError(code string, status int, path S3Path) error {
+	return newError(path, awserr.NewRequestFailure(
+		awserr.New(code, http.StatusText(status), nil),
+		status,
+		"",
+	))
+}
+
+// isFakeVersion returns true if the path addresses the only version of a fake object.
+func isFakeVersion(path S3Path) bool {
+	return path.VersionID == "" || path.VersionID == fakeNullVersionID
+}
+
+// isFakeErrorCode returns true if the err is an AWS error with the code provided.
+func isFakeErrorCode(err error, code string) bool {
+	var awsErr awserr.Error
+
+	return errors.As(err, &awsErr) && awsErr.Code() == code
+}
@@ -1,16 +1,108 @@
 package s3client
 
-import "time"
+import (
+	"context"
+	"io"
+	"time"
+)
 
 // S3Client is a client for AWS S3 storage.
 type S3Client interface {
+	S3ClientCtx
+
 	CreateBucket(bucket string) (string, error)
+	CreateBucketWithOptions(bucket string, opts CreateBucketOptions) (string, error)
+	BucketExists(bucket string) (bool, error)
+	DeleteBucket(bucket string, force bool) error
+	GetLifecycleRules(bucket string) ([]LifecycleRule, error)
+	SetLifecycleRules(bucket string, rules []LifecycleRule) error
+	GetBucketPolicy(bucket string) (string, error)
+	SetBucketPolicy(bucket, policy string) error
 	Exists(obj S3Path) (bool, error)
 	GetSize(obj S3Path, callerPays bool) (int64, error)
+	Stat(obj S3Path, callerPays bool) (ObjectInfo, error)
+	GetTags(obj S3Path) (map[string]string, error)
+	SetTags(obj S3Path, tags map[string]string) error
 	GetObject(objPath S3Path, callerPays bool) ([]byte, error)
+	GetObjectStream(objPath S3Path, callerPays bool, byteRange *ByteRange) (io.ReadCloser, error)
+	DownloadFile(objPath S3Path, localPath string, opts DownloadFileOptions) error
+	GetObjectIfChanged(objPath S3Path, callerPays bool, cond GetConditions) ([]byte, ObjectInfo, error)
+	PutObject(obj S3Path, body io.Reader) error
+	Upload(obj S3Path, body io.Reader, opts UploadOptions) error
+	UploadFile(localPath string, obj S3Path, opts UploadFileOptions) error
 	Delete(obj S3Path) error
+	DeleteMany(objs []S3Path) ([]DeleteFailure, error)
+	DeletePrefix(prefix S3Path) (int, error)
 	Copy(src, dst S3Path, validateEtag, callerPays bool) error
+	CopyObject(src, dst S3Path, opts CopyOptions) error
 	IsSrcNewer(src, dst S3Path, callerPays bool) (bool, error)
+	Sync(src, dst S3Path, opts SyncOptions) (*SyncReport, error)
+	SyncFromDir(localDir string, dst S3Path, opts SyncOptions) (*SyncReport, error)
 	GetPresignedURL(obj S3Path, duration time.Duration) (string, error)
+	GetPresignedPutURL(obj S3Path, duration time.Duration, contentType, contentMD5 string) (string, error)
+	GetPresignedDeleteURL(obj S3Path, duration time.Duration) (string, error)
+	GetPresignedPost(obj S3Path, duration time.Duration, opts PostPolicyOptions) (*PresignedPost, error)
 	GetETag(obj S3Path) (string, error)
+	List(prefix S3Path, delimiter string) *ObjectIterator
+	ListVersions(prefix S3Path) *ObjectIterator
+	ListIncompleteUploads(bucket, prefix string) ([]IncompleteUpload, error)
+	AbortUpload(upload IncompleteUpload) error
+	AbortStaleUploads(bucket string, olderThan time.Duration) (int, error)
+}
+
+// S3ClientCtx is a client for AWS S3 storage which methods accept a context to cancel a call.
+type S3ClientCtx interface {
+	CreateBucketWithContext(ctx context.Context, bucket string) (string, error)
+	CreateBucketWithOptionsWithContext(ctx context.Context, bucket string, opts CreateBucketOptions) (string, error)
+	BucketExistsWithContext(ctx context.Context, bucket string) (bool, error)
+	DeleteBucketWithContext(ctx context.Context, bucket string, force bool) error
+	GetLifecycleRulesWithContext(ctx context.Context, bucket string) ([]LifecycleRule, error)
+	SetLifecycleRulesWithContext(ctx context.Context, bucket string, rules []LifecycleRule) error
+	GetBucketPolicyWithContext(ctx context.Context, bucket string) (string, error)
+	SetBucketPolicyWithContext(ctx context.Context, bucket, policy string) error
+	ExistsWithContext(ctx context.Context, obj S3Path) (bool, error)
+	GetSizeWithContext(ctx context.Context, obj S3Path, callerPays bool) (int64, error)
+	StatWithContext(ctx context.Context, obj S3Path, callerPays bool) (ObjectInfo, error)
+	GetTagsWithContext(ctx context.Context, obj S3Path) (map[string]string, error)
+	SetTagsWithContext(ctx context.Context, obj S3Path, tags map[string]string) error
+	GetObjectWithContext(ctx context.Context, objPath S3Path, callerPays bool) ([]byte, error)
+	GetObjectStreamWithContext(ctx context.Context, objPath S3Path, callerPays bool, byteRange *ByteRange) (io.ReadCloser, error)
+	DownloadFileWithContext(ctx context.Context, objPath S3Path, localPath string, opts DownloadFileOptions) error
+	GetObjectIfChangedWithContext(
+		ctx context.Context,
+		objPath S3Path,
+		callerPays bool,
+		cond GetConditions,
+	) ([]byte, ObjectInfo, error)
+	PutObjectWithContext(ctx context.Context, obj S3Path, body io.Reader) error
+	UploadWithContext(ctx context.Context, obj S3Path, body io.Reader, opts UploadOptions) error
+	UploadFileWithContext(ctx context.Context, localPath string, obj S3Path, opts UploadFileOptions) error
+	DeleteWithContext(ctx context.Context, obj S3Path) error
+	DeleteManyWithContext(ctx context.Context, objs []S3Path) ([]DeleteFailure, error)
+	DeletePrefixWithContext(ctx context.Context, prefix S3Path) (int, error)
+	CopyWithContext(ctx context.Context, src, dst S3Path, validateEtag, callerPays bool) error
+	CopyObjectWithContext(ctx context.Context, src, dst S3Path, opts CopyOptions) error
+	IsSrcNewerWithContext(ctx context.Context, src, dst S3Path, callerPays bool) (bool, error)
+	SyncWithContext(ctx context.Context, src, dst S3Path, opts SyncOptions) (*SyncReport, error)
+	SyncFromDirWithContext(ctx context.Context, localDir string, dst S3Path, opts SyncOptions) (*SyncReport, error)
+	GetPresignedURLWithContext(ctx context.Context, obj S3Path, duration time.Duration) (string, error)
+	GetPresignedPutURLWithContext(
+		ctx context.Context,
+		obj S3Path,
+		duration time.Duration,
+		contentType, contentMD5 string,
+	) (string, error)
+	GetPresignedDeleteURLWithContext(ctx context.Context, obj S3Path, duration time.Duration) (string, error)
+	GetPresignedPostWithContext(
+		ctx context.Context,
+		obj S3Path,
+		duration time.Duration,
+		opts PostPolicyOptions,
+	) (*PresignedPost, error)
+	GetETagWithContext(ctx context.Context, obj S3Path) (string, error)
+	ListWithContext(ctx context.Context, prefix S3Path, delimiter string) *ObjectIterator
+	ListVersionsWithContext(ctx context.Context, prefix S3Path) *ObjectIterator
+	ListIncompleteUploadsWithContext(ctx context.Context, bucket, prefix string) ([]IncompleteUpload, error)
+	AbortUploadWithContext(ctx context.Context, upload IncompleteUpload) error
+	AbortStaleUploadsWithContext(ctx context.Context, bucket string, olderThan time.Duration) (int, error)
 }
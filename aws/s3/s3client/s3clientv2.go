@@ -0,0 +1,703 @@
+package s3client
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// s3ClientV2 is an S3Client backed by AWS SDK v2. It behaves the same way the SDK v1 one does,
+// so callers can migrate by replacing a constructor only.
+type s3ClientV2 struct {
+	backgroundClient
+
+	awsS3 *s3v2.Client
+	opts  clientOptions
+}
+
+// NewClientFromS3V2 sets the AWS SDK v2 S3 connection and returns an S3Client interface.
+func NewClientFromS3V2(awsS3client *s3v2.Client, opts ...ClientOption) S3Client {
+	c := &s3ClientV2{
+		awsS3: awsS3client,
+		opts:  newClientOptions(opts...),
+	}
+	c.backgroundClient = backgroundClient{c}
+
+	return c
+}
+
+// NewBucketClientFromS3V2 returns a bucket's client using the AWS SDK v2 S3 connection.
+func NewBucketClientFromS3V2(awsS3client *s3v2.Client, bucket string, opts ...ClientOption) BucketClient {
+	return NewBucketClientWithClient(NewClientFromS3V2(awsS3client, opts...), bucket)
+}
+
+// S3 returns an AWS SDK v2 S3 connection that was used when creating the object.
+func (c *s3ClientV2) S3() *s3v2.Client {
+	return c.awsS3
+}
+
+// GetObjectWithContext returns an S3 object in a byte array view.
+func (c *s3ClientV2) GetObjectWithContext(ctx context.Context, path S3Path, callerPays bool) ([]byte, error) {
+	body, err := c.GetObjectStreamWithContext(ctx, path, callerPays, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
+
+// GetObjectStreamWithContext returns a reader of an S3 object content. A nil byteRange means the whole object.
+// The caller is responsible for closing the reader returned.
+func (c *s3ClientV2) GetObjectStreamWithContext(
+	ctx context.Context,
+	path S3Path,
+	callerPays bool,
+	byteRange *ByteRange,
+) (io.ReadCloser, error) {
+	return c.getObjectRange(ctx, path, callerPays, byteRange, "")
+}
+
+// getObjectRange returns a reader of a byte range of an S3 object. ErrPreconditionFailed is returned
+// if ifMatch is set and the object ETag differs.
+func (c *s3ClientV2) getObjectRange(
+	ctx context.Context,
+	path S3Path,
+	callerPays bool,
+	byteRange *ByteRange,
+	ifMatch string,
+) (io.ReadCloser, error) {
+	params := &s3v2.GetObjectInput{
+		Bucket:              aws.String(path.Bucket),
+		Key:                 aws.String(path.Key),
+		VersionId:           path.versionID(),
+		RequestPayer:        c.opts.requestPayerV2(callerPays),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}
+	if byteRange != nil {
+		params.Range = aws.String(byteRange.String())
+	}
+	if ifMatch != "" {
+		params.IfMatch = aws.String(ifMatch)
+	}
+
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = c.opts.encryption.customerV2()
+
+	resp, err := c.awsS3.GetObject(ctx, params)
+	if err != nil {
+		return nil, newError(path, err)
+	}
+
+	return resp.Body, nil
+}
+
+// DownloadFileWithContext downloads an object to a local file by byte ranges fetched in parallel
+// and written to a temporary file, so the object is never buffered in memory. The temporary file is
+// renamed to the local one once its size and the object ETag are verified, so the local file is either
+// replaced by a complete object or left as is.
+func (c *s3ClientV2) DownloadFileWithContext(ctx context.Context, path S3Path, localPath string, opts DownloadFileOptions) error {
+	return downloadFile(ctx, c, path, localPath, opts)
+}
+
+// GetObjectIfChangedWithContext returns an S3 object content and attributes if the conditions are met
+// or ErrNotModified if the object has not been changed.
+func (c *s3ClientV2) GetObjectIfChangedWithContext(
+	ctx context.Context,
+	path S3Path,
+	callerPays bool,
+	cond GetConditions,
+) ([]byte, ObjectInfo, error) {
+	params := &s3v2.GetObjectInput{
+		Bucket:              aws.String(path.Bucket),
+		Key:                 aws.String(path.Key),
+		VersionId:           path.versionID(),
+		RequestPayer:        c.opts.requestPayerV2(callerPays),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}
+	if cond.IfNoneMatch != "" {
+		params.IfNoneMatch = aws.String(cond.IfNoneMatch)
+	}
+	if !cond.IfModifiedSince.IsZero() {
+		params.IfModifiedSince = aws.Time(cond.IfModifiedSince)
+	}
+
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = c.opts.encryption.customerV2()
+
+	resp, err := c.awsS3.GetObject(ctx, params)
+	if err != nil {
+		return nil, ObjectInfo{}, newError(path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, ObjectInfo{}, newError(path, err)
+	}
+
+	return data, newObjectInfoFromGetV2(path, resp), nil
+}
+
+// GetSizeWithContext returns a size in bytes of the object.
+func (c *s3ClientV2) GetSizeWithContext(ctx context.Context, path S3Path, callerPays bool) (int64, error) {
+	info, err := c.StatWithContext(ctx, path, callerPays)
+	if err != nil {
+		return -1, err
+	}
+
+	return info.Size, nil
+}
+
+// StatWithContext returns all the object attributes by a single HEAD request.
+func (c *s3ClientV2) StatWithContext(ctx context.Context, path S3Path, callerPays bool) (ObjectInfo, error) {
+	return c.stat(ctx, path, callerPays, c.opts.encryption)
+}
+
+// stat returns the object attributes using an SSE-C key of the encryption if any.
+func (c *s3ClientV2) stat(ctx context.Context, path S3Path, callerPays bool, encryption *Encryption) (ObjectInfo, error) {
+	head, err := c.awsS3.HeadObject(ctx, c.headInput(path, callerPays, encryption))
+	if err != nil {
+		return ObjectInfo{}, newError(path, err)
+	}
+
+	return newObjectInfoFromHeadV2(path, head), nil
+}
+
+// ExistsWithContext returns true if S3 object exists.
+func (c *s3ClientV2) ExistsWithContext(ctx context.Context, path S3Path) (bool, error) {
+	_, err := c.awsS3.HeadObject(ctx, c.headInput(path, false, c.opts.encryption))
+	if err != nil {
+		if errorCode(err) == awsErrNotFound {
+			return false, nil
+		}
+
+		return false, newError(path, err)
+	}
+
+	return true, nil
+}
+
+// headInput returns parameters of a HEAD request of the object.
+func (c *s3ClientV2) headInput(path S3Path, callerPays bool, encryption *Encryption) *s3v2.HeadObjectInput {
+	params := &s3v2.HeadObjectInput{
+		Bucket:              aws.String(path.Bucket),
+		Key:                 aws.String(path.Key),
+		VersionId:           path.versionID(),
+		RequestPayer:        c.opts.requestPayerV2(callerPays),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = encryption.customerV2()
+
+	return params
+}
+
+// multipartPartSize returns a size of the first part of a multipart object.
+func (c *s3ClientV2) multipartPartSize(ctx context.Context, path S3Path, callerPays bool, encryption *Encryption) (int64, error) {
+	params := c.headInput(path, callerPays, encryption)
+	params.PartNumber = aws.Int32(1)
+
+	head, err := c.awsS3.HeadObject(ctx, params)
+	if err != nil {
+		return 0, fmt.Errorf("error querying first part head: %w", newError(path, err))
+	}
+
+	return aws.ToInt64(head.ContentLength), nil
+}
+
+// GetTagsWithContext returns tags of the object.
+func (c *s3ClientV2) GetTagsWithContext(ctx context.Context, path S3Path) (map[string]string, error) {
+	resp, err := c.awsS3.GetObjectTagging(ctx, &s3v2.GetObjectTaggingInput{
+		Bucket:    aws.String(path.Bucket),
+		Key:       aws.String(path.Key),
+		VersionId: path.versionID(),
+
+		RequestPayer:        c.opts.requestPayerV2(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error querying tags: %w", newError(path, err))
+	}
+
+	tags := make(map[string]string, len(resp.TagSet))
+	for _, tag := range resp.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	return tags, nil
+}
+
+// SetTagsWithContext replaces tags of the object by the ones provided.
+func (c *s3ClientV2) SetTagsWithContext(ctx context.Context, path S3Path, tags map[string]string) error {
+	tagSet := make([]types.Tag, 0, len(tags))
+	for key, value := range tags {
+		tagSet = append(tagSet, types.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+
+	_, err := c.awsS3.PutObjectTagging(ctx, &s3v2.PutObjectTaggingInput{
+		Bucket:    aws.String(path.Bucket),
+		Key:       aws.String(path.Key),
+		VersionId: path.versionID(),
+		Tagging: &types.Tagging{
+			TagSet: tagSet,
+		},
+		RequestPayer:        c.opts.requestPayerV2(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("error setting tags: %w", newError(path, err))
+	}
+
+	return nil
+}
+
+// PutObjectWithContext uploads a body to the S3 path using default upload options.
+func (c *s3ClientV2) PutObjectWithContext(ctx context.Context, path S3Path, body io.Reader) error {
+	return c.UploadWithContext(ctx, path, body, UploadOptions{})
+}
+
+// UploadWithContext uploads a body to the S3 path. A body larger than a part size is uploaded
+// by a parallel multipart upload.
+func (c *s3ClientV2) UploadWithContext(ctx context.Context, path S3Path, body io.Reader, opts UploadOptions) error {
+	//nolint:staticcheck // transfermanager superseding the manager is not released as stable yet.
+	uploader := manager.NewUploader(c.awsS3, func(u *manager.Uploader) {
+		u.PartSize = DefaultMultipartChunkSize
+		if opts.PartSize > 0 {
+			u.PartSize = opts.PartSize
+		}
+
+		u.Concurrency = DefaultUploadConcurrency
+		if opts.Concurrency > 0 {
+			u.Concurrency = opts.Concurrency
+		}
+	})
+
+	input := &s3v2.PutObjectInput{
+		Bucket: aws.String(path.Bucket),
+		Key:    aws.String(path.Key),
+		Body:   body,
+
+		RequestPayer:        c.opts.requestPayerV2(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+		StorageClass:        types.StorageClass(c.opts.storageClass),
+	}
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+	}
+	if len(opts.Metadata) > 0 {
+		input.Metadata = opts.Metadata
+	}
+	if opts.IfAbsent {
+		// the uploader copies the condition to a request completing a multipart upload.
+		input.IfNoneMatch = aws.String(ifNoneMatchAny)
+	}
+
+	encryption := opts.Encryption
+	if encryption == nil {
+		encryption = c.opts.encryption
+	}
+	input.ServerSideEncryption, input.SSEKMSKeyId = encryption.serverSideV2()
+	input.SSECustomerAlgorithm, input.SSECustomerKey, input.SSECustomerKeyMD5 = encryption.customerV2()
+
+	_, err := uploader.Upload(ctx, input)
+
+	return newError(path, err)
+}
+
+// DeleteWithContext deletes an S3 object.
+func (c *s3ClientV2) DeleteWithContext(ctx context.Context, path S3Path) error {
+	_, err := c.awsS3.DeleteObject(ctx, &s3v2.DeleteObjectInput{
+		Bucket:              aws.String(path.Bucket),
+		Key:                 aws.String(path.Key),
+		VersionId:           path.versionID(),
+		RequestPayer:        c.opts.requestPayerV2(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		if errorCode(err) == awsErrNotFound {
+			return nil
+		}
+
+		return newError(path, err)
+	}
+
+	return nil
+}
+
+// DeleteManyWithContext deletes objects by batches of up to 1000 keys per request
+// and returns objects that were not deleted.
+func (c *s3ClientV2) DeleteManyWithContext(ctx context.Context, objs []S3Path) ([]DeleteFailure, error) {
+	return deleteMany(ctx, objs, c.deleteBatch)
+}
+
+// DeletePrefixWithContext deletes all objects which keys start with the prefix.Key
+// and returns a number of objects deleted. An empty prefix.Key is an error.
+func (c *s3ClientV2) DeletePrefixWithContext(ctx context.Context, prefix S3Path) (int, error) {
+	if err := checkDeletePrefix(prefix); err != nil {
+		return 0, err
+	}
+
+	return deletePrefix(ctx, prefix, c.ListWithContext, c.deleteBatch)
+}
+
+// deleteBatch implements deleteBatchFunc.
+func (c *s3ClientV2) deleteBatch(ctx context.Context, bucket string, objs []S3Path) ([]DeleteFailure, error) {
+	objects := make([]types.ObjectIdentifier, len(objs))
+	for i, obj := range objs {
+		objects[i] = types.ObjectIdentifier{
+			Key:       aws.String(obj.Key),
+			VersionId: obj.versionID(),
+		}
+	}
+
+	resp, err := c.awsS3.DeleteObjects(ctx, &s3v2.DeleteObjectsInput{
+		Bucket: aws.String(bucket),
+		Delete: &types.Delete{
+			Objects: objects,
+			Quiet:   aws.Bool(true),
+		},
+		RequestPayer:        c.opts.requestPayerV2(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		failures := make([]DeleteFailure, len(objs))
+		for i, obj := range objs {
+			failures[i] = DeleteFailure{
+				Path:    obj,
+				Message: err.Error(),
+			}
+		}
+
+		return failures, fmt.Errorf("error deleting %v objects: %w", len(objs), newError(S3Path{Bucket: bucket}, err))
+	}
+
+	failures := make([]DeleteFailure, 0, len(resp.Errors))
+	for _, e := range resp.Errors {
+		failures = append(failures, DeleteFailure{
+			Path: S3Path{
+				Bucket:    bucket,
+				Key:       aws.ToString(e.Key),
+				VersionID: aws.ToString(e.VersionId),
+			},
+			Code:    aws.ToString(e.Code),
+			Message: aws.ToString(e.Message),
+		})
+	}
+
+	return failures, nil
+}
+
+// IsSrcNewerWithContext returns true if source exist and newer thad destination, or when destination does not exist.
+func (c *s3ClientV2) IsSrcNewerWithContext(ctx context.Context, src, dst S3Path, callerPays bool) (bool, error) {
+	srcInfo, err := c.StatWithContext(ctx, src, callerPays)
+	if err != nil {
+		return false, fmt.Errorf("can not query source head: %w", err)
+	}
+
+	// check destination.
+	dstInfo, err := c.StatWithContext(ctx, dst, callerPays)
+	if err != nil {
+		if errorCode(err) == awsErrNotFound {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("error querying head: %w", err)
+	}
+
+	return srcInfo.LastModified.After(dstInfo.LastModified), nil
+}
+
+// SyncWithContext copies objects of the src prefix missing or changed in the dst prefix.
+func (c *s3ClientV2) SyncWithContext(ctx context.Context, src, dst S3Path, opts SyncOptions) (*SyncReport, error) {
+	return syncPrefix(ctx, c, src, dst, opts)
+}
+
+// SyncFromDirWithContext uploads files of the local directory missing or changed in the dst prefix.
+func (c *s3ClientV2) SyncFromDirWithContext(ctx context.Context, localDir string, dst S3Path, opts SyncOptions) (*SyncReport, error) {
+	return syncFromDir(ctx, c, localDir, dst, opts)
+}
+
+// GetETagWithContext returns an ETag of the object.
+func (c *s3ClientV2) GetETagWithContext(ctx context.Context, path S3Path) (string, error) {
+	info, err := c.StatWithContext(ctx, path, false)
+	if err != nil {
+		return "", fmt.Errorf("error querying head for ETag: %w", err)
+	}
+
+	if info.ETag == "" {
+		return "", fmt.Errorf("head returned a nil ETag %v", path)
+	}
+
+	return info.ETag, nil
+}
+
+// ListWithContext returns an iterator over objects of the prefix.Bucket which keys start with the prefix.Key.
+// Keys containing the delimiter after the prefix are rolled up into common prefixes when the delimiter is set.
+func (c *s3ClientV2) ListWithContext(ctx context.Context, prefix S3Path, delimiter string) *ObjectIterator {
+	return newObjectIterator(ctx, func(ctx context.Context, token string) ([]ObjectInfo, string, error) {
+		params := &s3v2.ListObjectsV2Input{
+			Bucket:              aws.String(prefix.Bucket),
+			RequestPayer:        c.opts.requestPayerV2(false),
+			ExpectedBucketOwner: c.opts.bucketOwner(),
+		}
+		if prefix.Key != "" {
+			params.Prefix = aws.String(prefix.Key)
+		}
+		if delimiter != "" {
+			params.Delimiter = aws.String(delimiter)
+		}
+		if token != "" {
+			params.ContinuationToken = aws.String(token)
+		}
+
+		resp, err := c.awsS3.ListObjectsV2(ctx, params)
+		if err != nil {
+			return nil, "", fmt.Errorf("error listing objects: %w", newError(prefix, err))
+		}
+
+		page := make([]ObjectInfo, 0, len(resp.CommonPrefixes)+len(resp.Contents))
+		for _, p := range resp.CommonPrefixes {
+			page = append(page, ObjectInfo{
+				Path: S3Path{
+					Bucket: prefix.Bucket,
+					Key:    aws.ToString(p.Prefix),
+				},
+				IsPrefix: true,
+			})
+		}
+
+		for _, obj := range resp.Contents {
+			page = append(page, ObjectInfo{
+				Path: S3Path{
+					Bucket: prefix.Bucket,
+					Key:    aws.ToString(obj.Key),
+				},
+				Size:         aws.ToInt64(obj.Size),
+				ETag:         aws.ToString(obj.ETag),
+				LastModified: aws.ToTime(obj.LastModified),
+				StorageClass: string(obj.StorageClass),
+			})
+		}
+
+		nextToken := ""
+		if aws.ToBool(resp.IsTruncated) {
+			nextToken = aws.ToString(resp.NextContinuationToken)
+		}
+
+		return page, nextToken, nil
+	})
+}
+
+// ListVersionsWithContext returns an iterator over all versions and delete markers of objects
+// of the prefix.Bucket which keys start with the prefix.Key. Versions of a key go from the latest one.
+func (c *s3ClientV2) ListVersionsWithContext(ctx context.Context, prefix S3Path) *ObjectIterator {
+	return newObjectIterator(ctx, func(ctx context.Context, token string) ([]ObjectInfo, string, error) {
+		params := &s3v2.ListObjectVersionsInput{
+			Bucket:              aws.String(prefix.Bucket),
+			ExpectedBucketOwner: c.opts.bucketOwner(),
+		}
+		if prefix.Key != "" {
+			params.Prefix = aws.String(prefix.Key)
+		}
+
+		if token != "" {
+			keyMarker, versionMarker, err := decodeVersionsToken(token)
+			if err != nil {
+				return nil, "", err
+			}
+			params.KeyMarker = aws.String(keyMarker)
+			params.VersionIdMarker = aws.String(versionMarker)
+		}
+
+		resp, err := c.awsS3.ListObjectVersions(ctx, params)
+		if err != nil {
+			return nil, "", fmt.Errorf("error listing object versions: %w", newError(prefix, err))
+		}
+
+		page := make([]ObjectInfo, 0, len(resp.Versions)+len(resp.DeleteMarkers))
+		for _, v := range resp.Versions {
+			page = append(page, ObjectInfo{
+				Path: S3Path{
+					Bucket:    prefix.Bucket,
+					Key:       aws.ToString(v.Key),
+					VersionID: aws.ToString(v.VersionId),
+				},
+				Size:         aws.ToInt64(v.Size),
+				ETag:         aws.ToString(v.ETag),
+				LastModified: aws.ToTime(v.LastModified),
+				StorageClass: string(v.StorageClass),
+				IsLatest:     aws.ToBool(v.IsLatest),
+			})
+		}
+
+		for _, m := range resp.DeleteMarkers {
+			page = append(page, ObjectInfo{
+				Path: S3Path{
+					Bucket:    prefix.Bucket,
+					Key:       aws.ToString(m.Key),
+					VersionID: aws.ToString(m.VersionId),
+				},
+				LastModified:   aws.ToTime(m.LastModified),
+				IsLatest:       aws.ToBool(m.IsLatest),
+				IsDeleteMarker: true,
+			})
+		}
+
+		sortVersions(page)
+
+		nextToken := ""
+		if aws.ToBool(resp.IsTruncated) {
+			nextToken = encodeVersionsToken(aws.ToString(resp.NextKeyMarker), aws.ToString(resp.NextVersionIdMarker))
+		}
+
+		return page, nextToken, nil
+	})
+}
+
+// ListIncompleteUploadsWithContext returns all incomplete multipart uploads of the bucket
+// which keys start with the prefix.
+func (c *s3ClientV2) ListIncompleteUploadsWithContext(ctx context.Context, bucket, prefix string) ([]IncompleteUpload, error) {
+	params := &s3v2.ListMultipartUploadsInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}
+	if prefix != "" {
+		params.Prefix = aws.String(prefix)
+	}
+
+	uploads := []IncompleteUpload{}
+	paginator := s3v2.NewListMultipartUploadsPaginator(c.awsS3, params)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing multipart uploads: %w", newError(S3Path{Bucket: bucket}, err))
+		}
+
+		for _, u := range page.Uploads {
+			uploads = append(uploads, IncompleteUpload{
+				Path: S3Path{
+					Bucket: bucket,
+					Key:    aws.ToString(u.Key),
+				},
+				UploadID:  aws.ToString(u.UploadId),
+				Initiated: aws.ToTime(u.Initiated),
+			})
+		}
+	}
+
+	return uploads, nil
+}
+
+// AbortUploadWithContext aborts an incomplete multipart upload and deletes its uploaded parts.
+func (c *s3ClientV2) AbortUploadWithContext(ctx context.Context, upload IncompleteUpload) error {
+	return c.abortMultipartUpload(ctx, upload.Path, upload.UploadID)
+}
+
+// AbortStaleUploadsWithContext aborts all incomplete multipart uploads of the bucket initiated
+// more than olderThan ago and returns a number of uploads aborted.
+func (c *s3ClientV2) AbortStaleUploadsWithContext(ctx context.Context, bucket string, olderThan time.Duration) (int, error) {
+	return abortStaleUploads(ctx, c, bucket, olderThan)
+}
+
+// abortMultipartUpload aborts a multipart upload by its ID.
+func (c *s3ClientV2) abortMultipartUpload(ctx context.Context, path S3Path, uploadID string) error {
+	_, err := c.awsS3.AbortMultipartUpload(ctx, &s3v2.AbortMultipartUploadInput{
+		Bucket:   aws.String(path.Bucket),
+		Key:      aws.String(path.Key),
+		UploadId: aws.String(uploadID),
+
+		RequestPayer:        c.opts.requestPayerV2(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("error aborting multipart upload %v : %w", uploadID, newError(path, err))
+	}
+
+	return nil
+}
+
+// abortFailedUpload aborts a multipart upload that failed with the err to not leave its parts behind
+// and returns the err extended with an abort error if any.
+func (c *s3ClientV2) abortFailedUpload(path S3Path, uploadID string, err error) error {
+	if abortErr := c.abortMultipartUpload(context.Background(), path, uploadID); abortErr != nil {
+		return fmt.Errorf("%w; %v", err, abortErr)
+	}
+
+	return err
+}
+
+// requestPayerV2 returns a request payer of an SDK v2 request, callerPays is a per-call setting.
+func (o clientOptions) requestPayerV2(callerPays bool) types.RequestPayer {
+	if callerPays || o.requesterPays {
+		return types.RequestPayerRequester
+	}
+
+	return ""
+}
+
+// serverSideV2 returns an encryption algorithm and a KMS key ID of an SDK v2 request.
+func (e *Encryption) serverSideV2() (types.ServerSideEncryption, *string) {
+	sse, kmsKeyID := e.serverSide()
+
+	return types.ServerSideEncryption(aws.ToString(sse)), kmsKeyID
+}
+
+// customerV2 returns SSE-C parameters of an SDK v2 request. Unlike SDK v1 one, SDK v2 sends the key
+// as is, so it is base64 encoded here.
+func (e *Encryption) customerV2() (algorithm, key, keyMD5 *string) {
+	algorithm, key, keyMD5 = e.customer()
+	if key != nil {
+		key = aws.String(base64.StdEncoding.EncodeToString(e.CustomerKey))
+	}
+
+	return algorithm, key, keyMD5
+}
+
+// newObjectInfoFromHeadV2 returns attributes of an object by its SDK v2 HEAD response.
+func newObjectInfoFromHeadV2(path S3Path, head *s3v2.HeadObjectOutput) ObjectInfo {
+	storageClass := string(head.StorageClass)
+	if storageClass == "" {
+		// HEAD omits the storage class of STANDARD objects.
+		storageClass = string(types.StorageClassStandard)
+	}
+
+	return ObjectInfo{
+		Path:            path,
+		Size:            aws.ToInt64(head.ContentLength),
+		ETag:            aws.ToString(head.ETag),
+		LastModified:    aws.ToTime(head.LastModified),
+		StorageClass:    storageClass,
+		ContentType:     aws.ToString(head.ContentType),
+		ContentEncoding: aws.ToString(head.ContentEncoding),
+		CacheControl:    aws.ToString(head.CacheControl),
+		Metadata:        head.Metadata,
+	}
+}
+
+// newObjectInfoFromGetV2 returns attributes of an object by its SDK v2 GET response.
+func newObjectInfoFromGetV2(path S3Path, resp *s3v2.GetObjectOutput) ObjectInfo {
+	storageClass := string(resp.StorageClass)
+	if storageClass == "" {
+		storageClass = string(types.StorageClassStandard)
+	}
+
+	return ObjectInfo{
+		Path:            path,
+		Size:            aws.ToInt64(resp.ContentLength),
+		ETag:            aws.ToString(resp.ETag),
+		LastModified:    aws.ToTime(resp.LastModified),
+		StorageClass:    storageClass,
+		ContentType:     aws.ToString(resp.ContentType),
+		ContentEncoding: aws.ToString(resp.ContentEncoding),
+		CacheControl:    aws.ToString(resp.CacheControl),
+		Metadata:        resp.Metadata,
+	}
+}
@@ -0,0 +1,266 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// CreateBucketWithContext creates a bucket in the client region. A bucket that is already owned
+// by the caller is not an error, ErrBucketAlreadyExists is returned if it is owned by another account.
+func (c *s3ClientV2) CreateBucketWithContext(ctx context.Context, bucket string) (string, error) {
+	return c.CreateBucketWithOptionsWithContext(ctx, bucket, CreateBucketOptions{})
+}
+
+// CreateBucketWithOptionsWithContext creates a bucket with the options provided and returns its location.
+// Versioning is enabled on a bucket that is already owned by the caller as well.
+func (c *s3ClientV2) CreateBucketWithOptionsWithContext(
+	ctx context.Context,
+	bucket string,
+	opts CreateBucketOptions,
+) (string, error) {
+	params := &s3v2.CreateBucketInput{
+		Bucket: aws.String(bucket),
+		ACL:    types.BucketCannedACL(opts.ACL),
+	}
+	if opts.ObjectLock {
+		params.ObjectLockEnabledForBucket = aws.Bool(true)
+	}
+
+	region := opts.Region
+	if region == "" {
+		region = c.awsS3.Options().Region
+	}
+	if region != "" && region != regionNoLocationConstraint {
+		params.CreateBucketConfiguration = &types.CreateBucketConfiguration{
+			LocationConstraint: types.BucketLocationConstraint(region),
+		}
+	}
+
+	location := "/" + bucket
+	// the request is sent to the bucket region endpoint.
+	resp, err := c.awsS3.CreateBucket(ctx, params, func(o *s3v2.Options) {
+		if region != "" {
+			o.Region = region
+		}
+	})
+	if err != nil && errorCode(err) != awsErrBucketOwned {
+		return "", newError(S3Path{Bucket: bucket}, err)
+	}
+	if err == nil && resp.Location != nil {
+		location = *resp.Location
+	}
+
+	if opts.Versioning {
+		_, err = c.awsS3.PutBucketVersioning(ctx, &s3v2.PutBucketVersioningInput{
+			Bucket: aws.String(bucket),
+			VersioningConfiguration: &types.VersioningConfiguration{
+				Status: types.BucketVersioningStatusEnabled,
+			},
+			ExpectedBucketOwner: c.opts.bucketOwner(),
+		}, func(o *s3v2.Options) {
+			if region != "" {
+				o.Region = region
+			}
+		})
+		if err != nil {
+			return "", fmt.Errorf("error enabling versioning: %w", newError(S3Path{Bucket: bucket}, err))
+		}
+	}
+
+	return location, nil
+}
+
+// BucketExistsWithContext returns true if the bucket exists and is accessible by the client.
+func (c *s3ClientV2) BucketExistsWithContext(ctx context.Context, bucket string) (bool, error) {
+	_, err := c.awsS3.HeadBucket(ctx, &s3v2.HeadBucketInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		err = newError(S3Path{Bucket: bucket}, err)
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DeleteBucketWithContext deletes the bucket. A bucket must be empty to be deleted, so force deletes
+// all its object versions, delete markers and incomplete multipart uploads first.
+func (c *s3ClientV2) DeleteBucketWithContext(ctx context.Context, bucket string, force bool) error {
+	if force {
+		if err := emptyBucket(ctx, c, bucket, c.deleteBatch); err != nil {
+			return err
+		}
+	}
+
+	_, err := c.awsS3.DeleteBucket(ctx, &s3v2.DeleteBucketInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting bucket: %w", newError(S3Path{Bucket: bucket}, err))
+	}
+
+	return nil
+}
+
+// GetLifecycleRulesWithContext returns lifecycle rules of the bucket, no rules are returned if none is set.
+func (c *s3ClientV2) GetLifecycleRulesWithContext(ctx context.Context, bucket string) ([]LifecycleRule, error) {
+	resp, err := c.awsS3.GetBucketLifecycleConfiguration(ctx, &s3v2.GetBucketLifecycleConfigurationInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		if errorCode(err) == awsErrNoSuchLifecycle {
+			return []LifecycleRule{}, nil
+		}
+
+		return nil, fmt.Errorf("error querying lifecycle rules: %w", newError(S3Path{Bucket: bucket}, err))
+	}
+
+	rules := make([]LifecycleRule, 0, len(resp.Rules))
+	for _, r := range resp.Rules {
+		rule := LifecycleRule{
+			ID:      aws.ToString(r.ID),
+			Prefix:  aws.ToString(r.Prefix), //nolint:staticcheck // rules created by old tools use it.
+			Enabled: r.Status == types.ExpirationStatusEnabled,
+		}
+		if r.Filter != nil && r.Filter.Prefix != nil {
+			rule.Prefix = *r.Filter.Prefix
+		}
+		if r.Expiration != nil {
+			rule.ExpirationDays = int(aws.ToInt32(r.Expiration.Days))
+		}
+		if r.NoncurrentVersionExpiration != nil {
+			rule.NoncurrentExpirationDays = int(aws.ToInt32(r.NoncurrentVersionExpiration.NoncurrentDays))
+		}
+		if r.AbortIncompleteMultipartUpload != nil {
+			rule.AbortIncompleteUploadDays = int(aws.ToInt32(r.AbortIncompleteMultipartUpload.DaysAfterInitiation))
+		}
+		if len(r.Transitions) > 0 {
+			rule.TransitionDays = int(aws.ToInt32(r.Transitions[0].Days))
+			rule.TransitionStorageClass = string(r.Transitions[0].StorageClass)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// SetLifecycleRulesWithContext replaces lifecycle rules of the bucket, empty rules delete the lifecycle configuration.
+func (c *s3ClientV2) SetLifecycleRulesWithContext(ctx context.Context, bucket string, rules []LifecycleRule) error {
+	if len(rules) == 0 {
+		_, err := c.awsS3.DeleteBucketLifecycle(ctx, &s3v2.DeleteBucketLifecycleInput{
+			Bucket:              aws.String(bucket),
+			ExpectedBucketOwner: c.opts.bucketOwner(),
+		})
+		if err != nil {
+			return fmt.Errorf("error deleting lifecycle rules: %w", newError(S3Path{Bucket: bucket}, err))
+		}
+
+		return nil
+	}
+
+	awsRules := make([]types.LifecycleRule, len(rules))
+	for i, rule := range rules {
+		r := types.LifecycleRule{
+			ID:     aws.String(rule.ID),
+			Status: types.ExpirationStatusDisabled,
+			Filter: &types.LifecycleRuleFilter{
+				Prefix: aws.String(rule.Prefix),
+			},
+		}
+		if rule.Enabled {
+			r.Status = types.ExpirationStatusEnabled
+		}
+		if rule.ExpirationDays > 0 {
+			r.Expiration = &types.LifecycleExpiration{
+				Days: aws.Int32(int32(rule.ExpirationDays)),
+			}
+		}
+		if rule.NoncurrentExpirationDays > 0 {
+			r.NoncurrentVersionExpiration = &types.NoncurrentVersionExpiration{
+				NoncurrentDays: aws.Int32(int32(rule.NoncurrentExpirationDays)),
+			}
+		}
+		if rule.AbortIncompleteUploadDays > 0 {
+			r.AbortIncompleteMultipartUpload = &types.AbortIncompleteMultipartUpload{
+				DaysAfterInitiation: aws.Int32(int32(rule.AbortIncompleteUploadDays)),
+			}
+		}
+		if rule.TransitionStorageClass != "" {
+			r.Transitions = []types.Transition{{
+				Days:         aws.Int32(int32(rule.TransitionDays)),
+				StorageClass: types.TransitionStorageClass(rule.TransitionStorageClass),
+			}}
+		}
+
+		awsRules[i] = r
+	}
+
+	_, err := c.awsS3.PutBucketLifecycleConfiguration(ctx, &s3v2.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+		LifecycleConfiguration: &types.BucketLifecycleConfiguration{
+			Rules: awsRules,
+		},
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("error setting lifecycle rules: %w", newError(S3Path{Bucket: bucket}, err))
+	}
+
+	return nil
+}
+
+// GetBucketPolicyWithContext returns a JSON policy of the bucket or an empty string if none is set.
+func (c *s3ClientV2) GetBucketPolicyWithContext(ctx context.Context, bucket string) (string, error) {
+	resp, err := c.awsS3.GetBucketPolicy(ctx, &s3v2.GetBucketPolicyInput{
+		Bucket:              aws.String(bucket),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		if errorCode(err) == awsErrNoSuchBucketPolicy {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("error querying bucket policy: %w", newError(S3Path{Bucket: bucket}, err))
+	}
+
+	return aws.ToString(resp.Policy), nil
+}
+
+// SetBucketPolicyWithContext replaces a JSON policy of the bucket, an empty policy deletes the bucket one.
+func (c *s3ClientV2) SetBucketPolicyWithContext(ctx context.Context, bucket, policy string) error {
+	if policy == "" {
+		_, err := c.awsS3.DeleteBucketPolicy(ctx, &s3v2.DeleteBucketPolicyInput{
+			Bucket:              aws.String(bucket),
+			ExpectedBucketOwner: c.opts.bucketOwner(),
+		})
+		if err != nil {
+			return fmt.Errorf("error deleting bucket policy: %w", newError(S3Path{Bucket: bucket}, err))
+		}
+
+		return nil
+	}
+
+	_, err := c.awsS3.PutBucketPolicy(ctx, &s3v2.PutBucketPolicyInput{
+		Bucket:              aws.String(bucket),
+		Policy:              aws.String(policy),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("error setting bucket policy: %w", newError(S3Path{Bucket: bucket}, err))
+	}
+
+	return nil
+}
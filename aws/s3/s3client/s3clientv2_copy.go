@@ -0,0 +1,193 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// CopyWithContext copies source to destination and checks if required the result integrity
+// by comparing an ETag of source and destination.
+func (c *s3ClientV2) CopyWithContext(ctx context.Context, src, dst S3Path, validateEtag, callerPays bool) error {
+	return c.CopyObjectWithContext(ctx, src, dst, CopyOptions{
+		ValidateETag: validateEtag,
+		CallerPays:   callerPays,
+	})
+}
+
+// CopyObjectWithContext copies source to destination using the options provided.
+func (c *s3ClientV2) CopyObjectWithContext(ctx context.Context, src, dst S3Path, opts CopyOptions) error {
+	return copyObject(ctx, c, src, dst, opts, c.opts.encryption)
+}
+
+// copySinglePart copies source to destination by a single CopyObject request.
+func (c *s3ClientV2) copySinglePart(ctx context.Context, src, dst S3Path, eTag string, opts CopyOptions) error {
+	copyParams := &s3v2.CopyObjectInput{
+		Bucket:     aws.String(dst.Bucket),
+		Key:        aws.String(dst.Key),
+		CopySource: aws.String(src.Path()),
+
+		RequestPayer:              c.opts.requestPayerV2(opts.CallerPays),
+		ExpectedBucketOwner:       c.opts.bucketOwner(),
+		ExpectedSourceBucketOwner: c.opts.bucketOwner(),
+		StorageClass:              types.StorageClass(c.opts.storageClass),
+	}
+	if opts.ReplaceMetadata {
+		copyParams.MetadataDirective = types.MetadataDirectiveReplace
+		copyParams.Metadata = opts.Metadata
+		if opts.ContentType != "" {
+			copyParams.ContentType = aws.String(opts.ContentType)
+		}
+	}
+
+	copyParams.ServerSideEncryption, copyParams.SSEKMSKeyId = opts.Encryption.serverSideV2()
+	copyParams.SSECustomerAlgorithm, copyParams.SSECustomerKey, copyParams.SSECustomerKeyMD5 = opts.Encryption.customerV2()
+	copyParams.CopySourceSSECustomerAlgorithm,
+		copyParams.CopySourceSSECustomerKey,
+		copyParams.CopySourceSSECustomerKeyMD5 = opts.SourceEncryption.customerV2()
+	if eTag != "" {
+		// the source must not change since its HEAD, ErrPreconditionFailed is returned otherwise.
+		copyParams.CopySourceIfMatch = aws.String(eTag)
+	}
+
+	copyResult, err := c.awsS3.CopyObject(ctx, copyParams)
+	if err != nil {
+		return newError(copyErrorPath(src, dst, err), err)
+	}
+
+	if opts.ValidateETag {
+		if copyResult.CopyObjectResult == nil || copyResult.CopyObjectResult.ETag == nil {
+			return fmt.Errorf("copy result is %+v cannot be verified using ETags", copyResult)
+		}
+
+		if eTag != *copyResult.CopyObjectResult.ETag {
+			return newETagMismatchError(dst, *copyResult.CopyObjectResult.ETag, eTag)
+		}
+	}
+
+	return nil
+}
+
+// createCopyUpload initiates a multipart upload of a copy and returns its ID.
+func (c *s3ClientV2) createCopyUpload(ctx context.Context, dst S3Path, srcInfo ObjectInfo, opts CopyOptions) (string, error) {
+	params := &s3v2.CreateMultipartUploadInput{
+		Bucket:              aws.String(dst.Bucket),
+		Key:                 aws.String(dst.Key),
+		RequestPayer:        c.opts.requestPayerV2(opts.CallerPays),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+		StorageClass:        types.StorageClass(c.opts.storageClass),
+	}
+	// unlike CopyObject a multipart upload does not copy the source metadata.
+	if opts.ReplaceMetadata {
+		params.Metadata = opts.Metadata
+		if opts.ContentType != "" {
+			params.ContentType = aws.String(opts.ContentType)
+		}
+	} else {
+		params.Metadata = srcInfo.Metadata
+		if srcInfo.ContentType != "" {
+			params.ContentType = aws.String(srcInfo.ContentType)
+		}
+		if srcInfo.ContentEncoding != "" {
+			params.ContentEncoding = aws.String(srcInfo.ContentEncoding)
+		}
+		if srcInfo.CacheControl != "" {
+			params.CacheControl = aws.String(srcInfo.CacheControl)
+		}
+	}
+
+	params.ServerSideEncryption, params.SSEKMSKeyId = opts.Encryption.serverSideV2()
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = opts.Encryption.customerV2()
+
+	upload, err := c.awsS3.CreateMultipartUpload(ctx, params)
+	if err != nil {
+		return "", newError(dst, err)
+	}
+
+	return aws.ToString(upload.UploadId), nil
+}
+
+// uploadPartCopy copies the byte range of the source as a part of a multipart upload and returns its ETag.
+// The source must still have the eTag if it is set, ErrPreconditionFailed is returned otherwise.
+func (c *s3ClientV2) uploadPartCopy(
+	ctx context.Context,
+	src, dst S3Path,
+	uploadID string,
+	partNumber int64,
+	byteRange *ByteRange,
+	eTag string,
+	opts CopyOptions,
+) (string, error) {
+	params := &s3v2.UploadPartCopyInput{
+		Bucket:          aws.String(dst.Bucket),
+		Key:             aws.String(dst.Key),
+		CopySource:      aws.String(src.Path()),
+		UploadId:        aws.String(uploadID),
+		CopySourceRange: aws.String(byteRange.String()),
+		PartNumber:      aws.Int32(int32(partNumber)),
+
+		RequestPayer:              c.opts.requestPayerV2(opts.CallerPays),
+		ExpectedBucketOwner:       c.opts.bucketOwner(),
+		ExpectedSourceBucketOwner: c.opts.bucketOwner(),
+	}
+	if eTag != "" {
+		params.CopySourceIfMatch = aws.String(eTag)
+	}
+
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = opts.Encryption.customerV2()
+	params.CopySourceSSECustomerAlgorithm,
+		params.CopySourceSSECustomerKey,
+		params.CopySourceSSECustomerKeyMD5 = opts.SourceEncryption.customerV2()
+
+	res, err := c.awsS3.UploadPartCopy(ctx, params)
+	if err != nil {
+		return "", newError(partCopyErrorPath(src, dst, err), err)
+	}
+
+	if res.CopyPartResult == nil {
+		return "", fmt.Errorf("copy result of part %v is empty", partNumber)
+	}
+
+	return aws.ToString(res.CopyPartResult.ETag), nil
+}
+
+// completeCopyUpload completes a multipart upload of a copy from the parts sorted by their numbers
+// and returns the result ETag.
+func (c *s3ClientV2) completeCopyUpload(
+	ctx context.Context,
+	dst S3Path,
+	uploadID string,
+	parts []uploadedPart,
+	opts CopyOptions,
+) (string, error) {
+	partsArr := make([]types.CompletedPart, len(parts))
+	for i, part := range parts {
+		partsArr[i] = types.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int32(int32(part.PartNumber)),
+		}
+	}
+
+	params := &s3v2.CompleteMultipartUploadInput{
+		Bucket:   aws.String(dst.Bucket),
+		Key:      aws.String(dst.Key),
+		UploadId: aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{
+			Parts: partsArr,
+		},
+
+		RequestPayer:        c.opts.requestPayerV2(opts.CallerPays),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = opts.Encryption.customerV2()
+
+	res, err := c.awsS3.CompleteMultipartUpload(ctx, params)
+	if err != nil {
+		return "", newError(dst, err)
+	}
+
+	return aws.ToString(res.ETag), nil
+}
@@ -0,0 +1,115 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// GetPresignedURLWithContext returns an S3 presigned URL for the given key.
+func (c *s3ClientV2) GetPresignedURLWithContext(ctx context.Context, path S3Path, duration time.Duration) (string, error) {
+	req, err := s3v2.NewPresignClient(c.awsS3).PresignGetObject(ctx, &s3v2.GetObjectInput{
+		Bucket:              aws.String(path.Bucket),
+		Key:                 aws.String(path.Key),
+		VersionId:           path.versionID(),
+		RequestPayer:        c.opts.requestPayerV2(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}, s3v2.WithPresignExpires(duration))
+
+	return presignedURL(req, path, err)
+}
+
+// GetPresignedPutURLWithContext returns an S3 presigned URL to upload the object by a PUT request.
+// Non-empty contentType and base64 encoded contentMD5 must be sent as the request headers with the same values.
+func (c *s3ClientV2) GetPresignedPutURLWithContext(
+	ctx context.Context,
+	path S3Path,
+	duration time.Duration,
+	contentType, contentMD5 string,
+) (string, error) {
+	params := &s3v2.PutObjectInput{
+		Bucket:              aws.String(path.Bucket),
+		Key:                 aws.String(path.Key),
+		RequestPayer:        c.opts.requestPayerV2(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+		StorageClass:        types.StorageClass(c.opts.storageClass),
+	}
+	if contentType != "" {
+		params.ContentType = aws.String(contentType)
+	}
+	if contentMD5 != "" {
+		params.ContentMD5 = aws.String(contentMD5)
+	}
+
+	req, err := s3v2.NewPresignClient(c.awsS3).PresignPutObject(ctx, params, s3v2.WithPresignExpires(duration))
+
+	return presignedURL(req, path, err)
+}
+
+// GetPresignedDeleteURLWithContext returns an S3 presigned URL to delete the object by a DELETE request.
+func (c *s3ClientV2) GetPresignedDeleteURLWithContext(ctx context.Context, path S3Path, duration time.Duration) (string, error) {
+	req, err := s3v2.NewPresignClient(c.awsS3).PresignDeleteObject(ctx, &s3v2.DeleteObjectInput{
+		Bucket:              aws.String(path.Bucket),
+		Key:                 aws.String(path.Key),
+		VersionId:           path.versionID(),
+		RequestPayer:        c.opts.requestPayerV2(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}, s3v2.WithPresignExpires(duration))
+
+	return presignedURL(req, path, err)
+}
+
+// GetPresignedPostWithContext returns a URL and form fields of a browser POST upload to the object path.
+func (c *s3ClientV2) GetPresignedPostWithContext(
+	ctx context.Context,
+	path S3Path,
+	duration time.Duration,
+	opts PostPolicyOptions,
+) (*PresignedPost, error) {
+	// a bucket request is presigned to get the bucket URL with the client addressing style.
+	req, err := s3v2.NewPresignClient(c.awsS3).PresignHeadBucket(ctx, &s3v2.HeadBucketInput{
+		Bucket: aws.String(path.Bucket),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error building a POST request %v : %w", path, err)
+	}
+
+	bucketURL, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing a POST URL %v : %w", path, err)
+	}
+	bucketURL.RawQuery = ""
+
+	clientOpts := c.awsS3.Options()
+	if clientOpts.Credentials == nil {
+		return nil, fmt.Errorf("no credentials to sign a POST policy %v", path)
+	}
+
+	creds, err := clientOpts.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting credentials to sign a POST policy %v : %w", path, err)
+	}
+
+	return newPresignedPost(bucketURL.String(), path, duration, opts, postPolicySigner{
+		accessKeyID:     creds.AccessKeyID,
+		secretAccessKey: creds.SecretAccessKey,
+		sessionToken:    creds.SessionToken,
+		region:          clientOpts.Region,
+		storageClass:    c.opts.storageClass,
+	})
+}
+
+// presignedURL returns a URL of the presigned request or the presigning error.
+func presignedURL(req *v4.PresignedHTTPRequest, path S3Path, err error) (string, error) {
+	if err != nil {
+		return "", fmt.Errorf("error presigning a request %v : %w", path, err)
+	}
+
+	return req.URL, nil
+}
@@ -0,0 +1,132 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// UploadFileWithContext uploads a local file to the S3 path. A file larger than a part size is uploaded
+// by a multipart upload which parts are read and uploaded in parallel. The upload progress is saved
+// to a checkpoint file, so a failed or interrupted upload resumes from the last completed part
+// when it is restarted. Such an upload is not aborted, AbortStaleUploads cleans up ones never resumed.
+func (c *s3ClientV2) UploadFileWithContext(ctx context.Context, localPath string, path S3Path, opts UploadFileOptions) error {
+	return uploadFile(ctx, c, localPath, path, opts)
+}
+
+// createMultipartUpload initiates a multipart upload and returns its ID.
+func (c *s3ClientV2) createMultipartUpload(ctx context.Context, path S3Path, opts UploadOptions) (string, error) {
+	params := &s3v2.CreateMultipartUploadInput{
+		Bucket: aws.String(path.Bucket),
+		Key:    aws.String(path.Key),
+
+		RequestPayer:        c.opts.requestPayerV2(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+		StorageClass:        types.StorageClass(c.opts.storageClass),
+	}
+	if opts.ContentType != "" {
+		params.ContentType = aws.String(opts.ContentType)
+	}
+	if len(opts.Metadata) > 0 {
+		params.Metadata = opts.Metadata
+	}
+
+	encryption := c.uploadEncryption(opts)
+	params.ServerSideEncryption, params.SSEKMSKeyId = encryption.serverSideV2()
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = encryption.customerV2()
+
+	upload, err := c.awsS3.CreateMultipartUpload(ctx, params)
+	if err != nil {
+		return "", fmt.Errorf("error creating multipart upload: %w", newError(path, err))
+	}
+
+	return aws.ToString(upload.UploadId), nil
+}
+
+// uploadPart uploads a part of a multipart upload and returns its ETag.
+func (c *s3ClientV2) uploadPart(
+	ctx context.Context,
+	path S3Path,
+	uploadID string,
+	partNumber int64,
+	body io.ReadSeeker,
+	opts UploadOptions,
+) (string, error) {
+	params := &s3v2.UploadPartInput{
+		Bucket:     aws.String(path.Bucket),
+		Key:        aws.String(path.Key),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int32(int32(partNumber)),
+		Body:       body,
+
+		RequestPayer:        c.opts.requestPayerV2(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = c.uploadEncryption(opts).customerV2()
+
+	// part checksums are not requested on the upload creation, so they must not be sent with parts either.
+	res, err := c.awsS3.UploadPart(ctx, params, func(o *s3v2.Options) {
+		o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+	})
+	if err != nil {
+		return "", newError(path, err)
+	}
+
+	return aws.ToString(res.ETag), nil
+}
+
+// completeMultipartUpload completes a multipart upload of the parts sorted by their numbers.
+func (c *s3ClientV2) completeMultipartUpload(
+	ctx context.Context,
+	path S3Path,
+	uploadID string,
+	parts []uploadedPart,
+	opts UploadOptions,
+) error {
+	partsArr := make([]types.CompletedPart, len(parts))
+	for i, part := range parts {
+		partsArr[i] = types.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int32(int32(part.PartNumber)),
+		}
+	}
+	sort.Slice(partsArr, func(i, j int) bool {
+		return aws.ToInt32(partsArr[i].PartNumber) < aws.ToInt32(partsArr[j].PartNumber)
+	})
+
+	params := &s3v2.CompleteMultipartUploadInput{
+		Bucket:   aws.String(path.Bucket),
+		Key:      aws.String(path.Key),
+		UploadId: aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{
+			Parts: partsArr,
+		},
+
+		RequestPayer:        c.opts.requestPayerV2(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}
+	if opts.IfAbsent {
+		params.IfNoneMatch = aws.String(ifNoneMatchAny)
+	}
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = c.uploadEncryption(opts).customerV2()
+
+	if _, err := c.awsS3.CompleteMultipartUpload(ctx, params); err != nil {
+		return fmt.Errorf("error completing multipart upload: %w", newError(path, err))
+	}
+
+	return nil
+}
+
+// uploadEncryption returns an encryption of the upload or the client one if it is not set.
+func (c *s3ClientV2) uploadEncryption(opts UploadOptions) *Encryption {
+	if opts.Encryption != nil {
+		return opts.Encryption
+	}
+
+	return c.opts.encryption
+}
@@ -2,47 +2,374 @@ package s3client
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"regexp"
 	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
 )
 
 const (
-	s3Schema = "s3://"
+	s3Schema       = "s3://"
+	versionIDQuery = "?versionId="
+
+	bucketNameMinLen = 3
+	bucketNameMaxLen = 63
+
+	arnPrefix          = "arn:"
+	arnSections        = 6
+	arnAccessPoint     = "accesspoint/"
+	arnAccessPointKey  = "/object/"
+	awsHostSuffix      = ".amazonaws.com"
+	awsChinaHostSuffix = ".amazonaws.com.cn"
+	s3HostLabel        = "s3"
+	s3DualStackLabel   = "dualstack"
+	urlVersionIDParam  = "versionId"
+	keySeparator       = "/"
 )
 
+var (
+	// awsRegionLabel matches a region host label, e.g. eu-west-1 or us-gov-east-1.
+	awsRegionLabel = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-[0-9]+$`)
+	// s3EndpointLabel matches an S3 endpoint host label: s3, s3-[region], s3-accelerate, s3-external-1 or s3-fips.
+	s3EndpointLabel = regexp.MustCompile(`^s3(-accelerate|-external-1|-fips(-[a-z]{2}(-gov)?-[a-z]+-[0-9]+)?|-[a-z]{2}(-gov)?-[a-z]+-[0-9]+)?$`)
+)
+
+// schemas of S3 URIs, s3a:// is used by Hadoop tools.
+var s3Schemas = []string{s3Schema, "s3a://", "s3n://"}
+
 // S3Path contains a ful path to a bucket object.
 type S3Path struct {
 	Bucket string
 	Key    string
+	// VersionID addresses a specific version of an object, the latest one is used when empty.
+	VersionID string
 }
 
-// NewS3Object creates a path object from a path: [bucket]/[key].
+// NewS3Object creates a path object from a path in one of the forms:
+//   - [bucket]/[key] optionally prefixed by s3://, s3a:// or s3n://;
+//   - https://[bucket].s3.[region].amazonaws.com/[key] virtual-hosted style URL;
+//   - https://s3.[region].amazonaws.com/[bucket]/[key] or a custom endpoint path-style URL;
+//   - arn:aws:s3:::[bucket]/[key] object ARN;
+//   - arn:aws:s3:[region]:[account]:accesspoint/[name]/object/[key] access point ARN,
+//     the access point ARN is used as a bucket then.
+//
+// A version is set by the ?versionId=[version] suffix. A bucket name is validated against S3 naming rules.
 func NewS3Object(path string) (S3Path, error) {
+	p, err := parseS3Path(path)
+	if err != nil {
+		return S3Path{}, err
+	}
+
+	if p.Key == "" {
+		return S3Path{}, ErrPathNoKey
+	}
+
+	return p, nil
+}
+
+// parseS3Path parses a path of any supported form allowing an empty key.
+func parseS3Path(path string) (S3Path, error) {
+	lowerPath := strings.ToLower(path)
+	switch {
+	case strings.HasPrefix(lowerPath, "https://"), strings.HasPrefix(lowerPath, "http://"):
+		return parseS3URL(path)
+	case strings.HasPrefix(lowerPath, arnPrefix):
+		return parseS3ARN(path)
+	}
+
 	fullPath := path
-	if strings.HasPrefix(strings.ToLower(path), s3Schema) {
-		fullPath = path[5:]
+	for _, schema := range s3Schemas {
+		if strings.HasPrefix(lowerPath, schema) {
+			fullPath = path[len(schema):]
+			break
+		}
+	}
+
+	versionID := ""
+	if idx := strings.LastIndex(fullPath, versionIDQuery); idx != -1 {
+		versionID = fullPath[idx+len(versionIDQuery):]
+		fullPath = fullPath[:idx]
 	}
 
-	bucketIdx := strings.Index(fullPath, "/")
+	if strings.HasPrefix(strings.ToLower(fullPath), arnPrefix) {
+		// an access point ARN is used as a bucket, so it is a part of a full path of an access point object.
+		return parseAccessPointPath(fullPath, versionID)
+	}
+
+	bucketIdx := strings.Index(fullPath, keySeparator)
 	if bucketIdx == -1 {
 		return S3Path{}, ErrPathNoBucketSeparator
 	}
 
-	if bucketIdx >= len(fullPath)-1 {
-		return S3Path{}, ErrPathNoKey
+	p := S3Path{
+		Bucket:    fullPath[:bucketIdx],
+		Key:       fullPath[bucketIdx+1:],
+		VersionID: versionID,
+	}
+
+	return p, ValidateBucketName(p.Bucket)
+}
+
+// parseAccessPointPath parses a path which bucket is an access point ARN: arn:...:accesspoint/[name]/[key].
+func parseAccessPointPath(fullPath, versionID string) (S3Path, error) {
+	idx := strings.Index(fullPath, arnAccessPoint)
+	if idx == -1 {
+		return S3Path{}, fmt.Errorf("%w: not an access point %v", ErrPathInvalidARN, fullPath)
+	}
+
+	accessPoint, key := fullPath, ""
+	nameStart := idx + len(arnAccessPoint)
+	if keyIdx := strings.Index(fullPath[nameStart:], keySeparator); keyIdx != -1 {
+		accessPoint, key = fullPath[:nameStart+keyIdx], fullPath[nameStart+keyIdx+1:]
+	}
+
+	p, err := parseS3ARN(accessPoint)
+	if err != nil {
+		return S3Path{}, err
+	}
+
+	p.Key, p.VersionID = key, versionID
+
+	return p, nil
+}
+
+// parseS3URL parses a virtual-hosted or a path-style HTTP(S) URL of an object.
+func parseS3URL(rawURL string) (S3Path, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return S3Path{}, fmt.Errorf("%w: %v", ErrPathInvalidURL, err)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	urlPath := strings.TrimPrefix(u.Path, keySeparator)
+
+	p := S3Path{
+		VersionID: u.Query().Get(urlVersionIDParam),
+	}
+
+	if bucket, ok := virtualHostedBucket(host); ok {
+		p.Bucket, p.Key = bucket, urlPath
+	} else {
+		// a path-style URL of AWS or of an S3 compatible storage.
+		bucketIdx := strings.Index(urlPath, keySeparator)
+		if bucketIdx == -1 {
+			p.Bucket = urlPath
+		} else {
+			p.Bucket, p.Key = urlPath[:bucketIdx], urlPath[bucketIdx+1:]
+		}
+	}
+
+	if p.Bucket == "" {
+		return S3Path{}, fmt.Errorf("%w: no bucket in %v", ErrPathInvalidURL, rawURL)
+	}
+
+	return p, ValidateBucketName(p.Bucket)
+}
+
+// virtualHostedBucket returns a bucket of an AWS virtual-hosted style host: [bucket].s3[-.][region].amazonaws.com.
+func virtualHostedBucket(host string) (string, bool) {
+	suffix := awsHostSuffix
+	if strings.HasSuffix(host, awsChinaHostSuffix) {
+		suffix = awsChinaHostSuffix
+	} else if !strings.HasSuffix(host, awsHostSuffix) {
+		return "", false
+	}
+
+	// bucket names may contain dots and S3-like labels, so an endpoint label is searched from the end:
+	// only dualstack and region labels may follow it. The first label is always a bucket one.
+	labels := strings.Split(strings.TrimSuffix(host, suffix), ".")
+	for i := len(labels) - 1; i > 0; i-- {
+		if s3EndpointLabel.MatchString(labels[i]) {
+			return strings.Join(labels[:i], "."), true
+		}
+
+		if labels[i] != s3DualStackLabel && !awsRegionLabel.MatchString(labels[i]) {
+			break
+		}
+	}
+
+	return "", false
+}
+
+// parseS3ARN parses an S3 object or an access point object ARN.
+func parseS3ARN(arn string) (S3Path, error) {
+	sections := strings.SplitN(arn, ":", arnSections)
+	if len(sections) != arnSections || sections[2] != s3HostLabel {
+		return S3Path{}, fmt.Errorf("%w: %v", ErrPathInvalidARN, arn)
+	}
+
+	resource := sections[5]
+	if !strings.HasPrefix(resource, arnAccessPoint) {
+		// an object ARN has neither a region nor an account: arn:aws:s3:::bucket/key.
+		if sections[3] != "" || sections[4] != "" {
+			return S3Path{}, fmt.Errorf("%w: %v", ErrPathInvalidARN, arn)
+		}
+
+		return parseS3Path(resource)
+	}
+
+	versionID := ""
+	if idx := strings.LastIndex(resource, versionIDQuery); idx != -1 {
+		versionID = resource[idx+len(versionIDQuery):]
+		resource = resource[:idx]
+	}
+
+	// AWS SDK accepts an access point ARN as a bucket name.
+	accessPoint, key := resource, ""
+	if idx := strings.Index(resource, arnAccessPointKey); idx != -1 {
+		accessPoint, key = resource[:idx], resource[idx+len(arnAccessPointKey):]
+	}
+
+	if accessPoint == arnAccessPoint {
+		return S3Path{}, fmt.Errorf("%w: no access point name in %v", ErrPathInvalidARN, arn)
 	}
 
 	return S3Path{
-		Bucket: fullPath[:bucketIdx],
-		Key:    fullPath[bucketIdx+1:],
+		Bucket:    strings.Join(sections[:5], ":") + ":" + accessPoint,
+		Key:       key,
+		VersionID: versionID,
 	}, nil
 }
 
-// Path returns an S3 path constructed.
-func (p *S3Path) Path() string {
-	return fmt.Sprintf("%v/%v", p.Bucket, p.Key)
+// ValidateBucketName checks a bucket name against S3 naming rules: 3-63 characters long, lowercase letters,
+// digits, dots and hyphens only, starts and ends with a letter or a digit, is not formatted as an IP address.
+func ValidateBucketName(bucket string) error {
+	if len(bucket) < bucketNameMinLen || len(bucket) > bucketNameMaxLen {
+		return fmt.Errorf("%w %q: must be %v-%v characters long", ErrInvalidBucketName, bucket, bucketNameMinLen, bucketNameMaxLen)
+	}
+
+	for _, r := range bucket {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-') {
+			return fmt.Errorf("%w %q: invalid character %q", ErrInvalidBucketName, bucket, r)
+		}
+	}
+
+	if !isBucketNameEdge(bucket[0]) || !isBucketNameEdge(bucket[len(bucket)-1]) {
+		return fmt.Errorf("%w %q: must start and end with a letter or a digit", ErrInvalidBucketName, bucket)
+	}
+
+	if strings.Contains(bucket, "..") {
+		return fmt.Errorf("%w %q: must not contain adjacent dots", ErrInvalidBucketName, bucket)
+	}
+
+	if net.ParseIP(bucket) != nil {
+		return fmt.Errorf("%w %q: must not be formatted as an IP address", ErrInvalidBucketName, bucket)
+	}
+
+	return nil
+}
+
+// isBucketNameEdge returns true if a bucket name may start or end with the character.
+func isBucketNameEdge(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9'
+}
+
+// Path returns an S3 path constructed, it is used as a copy source, so it includes a version if set.
+func (p S3Path) Path() string {
+	return fmt.Sprintf("%v/%v%v", p.Bucket, p.Key, p.versionSuffix())
 }
 
 // FullPath returns a full S3 path constructed including the schema.
-func (p *S3Path) FullPath() string {
-	return fmt.Sprintf("%s%v/%v", s3Schema, p.Bucket, p.Key)
+func (p S3Path) FullPath() string {
+	return fmt.Sprintf("%s%v/%v%v", s3Schema, p.Bucket, p.Key, p.versionSuffix())
+}
+
+// String implements fmt.Stringer interface returning a full path.
+func (p S3Path) String() string {
+	return p.FullPath()
+}
+
+// WithKey returns a path of another object of the same bucket.
+func (p S3Path) WithKey(key string) S3Path {
+	return S3Path{
+		Bucket: p.Bucket,
+		Key:    key,
+	}
+}
+
+// Join returns a path of the bucket which key is the path key joined with the elements by slashes.
+// Unlike path.Join, elements are not cleaned since S3 keys may contain any characters.
+func (p S3Path) Join(elem ...string) S3Path {
+	key := p.Key
+	for _, e := range elem {
+		e = strings.TrimPrefix(e, keySeparator)
+		if e == "" {
+			continue
+		}
+
+		if key != "" && !strings.HasSuffix(key, keySeparator) {
+			key += keySeparator
+		}
+		key += e
+	}
+
+	return p.WithKey(key)
+}
+
+// Dir returns a path of the prefix containing the object, its key ends with a slash
+// or is empty for an object at the bucket root.
+func (p S3Path) Dir() S3Path {
+	idx := strings.LastIndex(strings.TrimSuffix(p.Key, keySeparator), keySeparator)
+
+	return p.WithKey(p.Key[:idx+1])
+}
+
+// Base returns the last element of the key ignoring a trailing slash.
+func (p S3Path) Base() string {
+	key := strings.TrimSuffix(p.Key, keySeparator)
+
+	return key[strings.LastIndex(key, keySeparator)+1:]
+}
+
+// Ext returns the file name extension of the key including the dot or an empty string if there is none.
+func (p S3Path) Ext() string {
+	return path.Ext(p.Base())
+}
+
+// MarshalText implements encoding.TextMarshaler interface, so the path is encoded as a string in JSON.
+func (p S3Path) MarshalText() ([]byte, error) {
+	if p == (S3Path{}) {
+		return []byte{}, nil
+	}
+
+	return []byte(p.FullPath()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface accepting any form NewS3Object does
+// and an empty key.
+func (p *S3Path) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*p = S3Path{}
+		return nil
+	}
+
+	parsed, err := parseS3Path(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = parsed
+
+	return nil
+}
+
+// versionSuffix returns a version query of the path or an empty string if no version is set.
+func (p S3Path) versionSuffix() string {
+	if p.VersionID == "" {
+		return ""
+	}
+
+	return versionIDQuery + p.VersionID
+}
+
+// versionID returns a version of the path to set to a request or nil if no version is set.
+func (p S3Path) versionID() *string {
+	if p.VersionID == "" {
+		return nil
+	}
+
+	return aws.String(p.VersionID)
 }
@@ -0,0 +1,357 @@
+package s3client
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // MD5 is required to compute S3 ETags.
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FurmanovD/go-kit/filesys/fsops"
+)
+
+// SyncCompare is a set of object attributes compared to decide if an object has to be copied.
+type SyncCompare int
+
+const (
+	// SyncCompareSize copies an object when sizes of source and destination differ.
+	SyncCompareSize SyncCompare = 1 << iota
+	// SyncCompareETag copies an object when ETags of source and destination differ.
+	SyncCompareETag
+	// SyncCompareModTime copies an object when source is newer than destination.
+	SyncCompareModTime
+
+	// DefaultSyncCompare is used when no comparison is set.
+	DefaultSyncCompare = SyncCompareSize | SyncCompareModTime
+
+	// DefaultSyncConcurrency is a number of objects copied in parallel by default.
+	DefaultSyncConcurrency = 10
+)
+
+// SyncOptions contains parameters of a sync operation.
+type SyncOptions struct {
+	// Compare is a set of attributes compared. DefaultSyncCompare is used when 0.
+	Compare SyncCompare
+	// DeleteExtraneous deletes destination objects that do not exist in source.
+	DeleteExtraneous bool
+	// DryRun only reports the changes without applying them.
+	DryRun bool
+	// CallerPays is set when the requester pays for the source objects access.
+	CallerPays bool
+	// Concurrency is a number of objects copied in parallel. DefaultSyncConcurrency is used when 0.
+	Concurrency int
+}
+
+// SyncReport contains keys relative to the source and destination prefixes processed by a sync.
+type SyncReport struct {
+	Copied  []string
+	Skipped []string
+	Deleted []string
+}
+
+// syncEntry is a source object of a sync.
+type syncEntry struct {
+	rel          string
+	size         int64
+	lastModified time.Time
+	// eTag returns an ETag of the source, it is evaluated lazily as a local file hash is expensive.
+	eTag func() (string, error)
+	// copyTo copies the source to the destination path.
+	copyTo func(ctx context.Context, dst S3Path) error
+}
+
+// SyncWithContext makes objects of the dst prefix the same as the ones of the src prefix.
+func (c *s3Client) SyncWithContext(ctx context.Context, src, dst S3Path, opts SyncOptions) (*SyncReport, error) {
+	return syncPrefix(ctx, c, src, dst, opts)
+}
+
+// SyncFromDirWithContext makes objects of the dst prefix the same as files of the local directory.
+func (c *s3Client) SyncFromDirWithContext(ctx context.Context, localDir string, dst S3Path, opts SyncOptions) (*SyncReport, error) {
+	return syncFromDir(ctx, c, localDir, dst, opts)
+}
+
+// syncPrefix syncs S3 prefixes using the client provided.
+func syncPrefix(ctx context.Context, client S3Client, src, dst S3Path, opts SyncOptions) (*SyncReport, error) {
+	objects, err := client.ListWithContext(ctx, src, "").All()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]syncEntry, 0, len(objects))
+	for _, obj := range objects {
+		obj := obj
+		entries = append(entries, syncEntry{
+			rel:          strings.TrimPrefix(obj.Path.Key, src.Key),
+			size:         obj.Size,
+			lastModified: obj.LastModified,
+			eTag: func() (string, error) {
+				return obj.ETag, nil
+			},
+			copyTo: func(ctx context.Context, dstPath S3Path) error {
+				return client.CopyObjectWithContext(ctx, obj.Path, dstPath, CopyOptions{CallerPays: opts.CallerPays})
+			},
+		})
+	}
+
+	return syncEntries(ctx, client, entries, dst, opts)
+}
+
+// syncFromDir syncs a local directory to an S3 prefix using the client provided.
+func syncFromDir(ctx context.Context, client S3Client, localDir string, dst S3Path, opts SyncOptions) (*SyncReport, error) {
+	if !fsops.IsDir(localDir) {
+		return nil, fmt.Errorf("%s source is not a directory or inaccessible", localDir)
+	}
+
+	entries := []syncEntry{}
+	err := filepath.WalkDir(localDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(localDir, path)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, syncEntry{
+			rel:          filepath.ToSlash(rel),
+			size:         info.Size(),
+			lastModified: info.ModTime(),
+			eTag: func() (string, error) {
+				return fileETag(path, DefaultMultipartChunkSize)
+			},
+			copyTo: func(ctx context.Context, dstPath S3Path) error {
+				file, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+
+				return client.UploadWithContext(ctx, dstPath, file, UploadOptions{})
+			},
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading directory %s: %w", localDir, err)
+	}
+
+	return syncEntries(ctx, client, entries, dst, opts)
+}
+
+// syncEntries copies the source entries that differ from the destination objects
+// and deletes extraneous destination objects if required.
+// nolint:funlen,gocyclo
+func syncEntries(ctx context.Context, client S3Client, entries []syncEntry, dst S3Path, opts SyncOptions) (*SyncReport, error) {
+	if opts.Compare == 0 {
+		opts.Compare = DefaultSyncCompare
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultSyncConcurrency
+	}
+
+	dstObjects, err := client.ListWithContext(ctx, dst, "").All()
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]ObjectInfo, len(dstObjects))
+	for _, obj := range dstObjects {
+		existing[strings.TrimPrefix(obj.Path.Key, dst.Key)] = obj
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	report := &SyncReport{
+		Copied:  []string{},
+		Skipped: []string{},
+		Deleted: []string{},
+	}
+	semaphore := make(chan struct{}, opts.Concurrency)
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+
+		dstObj, exists := existing[entry.rel]
+		delete(existing, entry.rel)
+
+		changed := !exists
+		if exists {
+			changed, err = isEntryChanged(entry, dstObj, opts.Compare)
+			if err != nil {
+				setErr(err)
+				break
+			}
+		}
+
+		if !changed {
+			report.Skipped = append(report.Skipped, entry.rel)
+			continue
+		}
+
+		if opts.DryRun {
+			report.Copied = append(report.Copied, entry.rel)
+			continue
+		}
+
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func(entry syncEntry) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			dstPath := S3Path{
+				Bucket: dst.Bucket,
+				Key:    dst.Key + entry.rel,
+			}
+			if err := entry.copyTo(ctx, dstPath); err != nil {
+				setErr(fmt.Errorf("error syncing %v : %w", dstPath, err))
+				return
+			}
+
+			// only the entries copied are reported, so a failed sync report lists what has been done.
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Copied = append(report.Copied, entry.rel)
+		}(entry)
+	}
+
+	wg.Wait()
+	sort.Strings(report.Copied)
+
+	if firstErr != nil {
+		return report, firstErr
+	}
+
+	// the entries left unprocessed keep their destination objects in existing, so they are not extraneous.
+	if ctx.Err() != nil {
+		return report, fmt.Errorf("sync cancelled: %w", ctx.Err())
+	}
+
+	if !opts.DeleteExtraneous || len(existing) == 0 {
+		return report, nil
+	}
+
+	extraneous := make([]S3Path, 0, len(existing))
+	for rel, obj := range existing {
+		report.Deleted = append(report.Deleted, rel)
+		extraneous = append(extraneous, obj.Path)
+	}
+	sort.Strings(report.Deleted)
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	failures, err := client.DeleteManyWithContext(ctx, extraneous)
+	if err != nil {
+		return report, err
+	}
+
+	if len(failures) > 0 {
+		return report, fmt.Errorf("failed to delete %v extraneous object(s), first one: %w", len(failures), failures[0])
+	}
+
+	return report, nil
+}
+
+// isEntryChanged compares a source entry with a destination object.
+func isEntryChanged(entry syncEntry, dst ObjectInfo, compare SyncCompare) (bool, error) {
+	if compare&SyncCompareSize != 0 && entry.size != dst.Size {
+		return true, nil
+	}
+
+	if compare&SyncCompareModTime != 0 && entry.lastModified.After(dst.LastModified) {
+		return true, nil
+	}
+
+	if compare&SyncCompareETag != 0 {
+		eTag, err := entry.eTag()
+		if err != nil {
+			return false, err
+		}
+
+		if eTag != dst.ETag {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// fileETag computes an ETag a local file gets when uploaded using the part size provided.
+func fileETag(path string, partSize int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	if info.Size() <= partSize {
+		hash := md5.New() //nolint:gosec // MD5 is required to compute S3 ETags.
+		if _, err = io.Copy(hash, file); err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf(`"%s"`, hex.EncodeToString(hash.Sum(nil))), nil
+	}
+
+	partETags := []string{}
+	for {
+		hash := md5.New() //nolint:gosec // MD5 is required to compute S3 ETags.
+		n, err := io.CopyN(hash, file, partSize)
+		if n > 0 {
+			partETags = append(partETags, hex.EncodeToString(hash.Sum(nil)))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return compositeETag(partETags)
+}
@@ -0,0 +1,58 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// GetTagsWithContext returns tags of the object.
+func (c *s3Client) GetTagsWithContext(ctx context.Context, path S3Path) (map[string]string, error) {
+	resp, err := c.awsS3.GetObjectTaggingWithContext(ctx, &s3.GetObjectTaggingInput{
+		Bucket:    aws.String(path.Bucket),
+		Key:       aws.String(path.Key),
+		VersionId: path.versionID(),
+
+		RequestPayer:        c.opts.requestPayer(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error querying tags: %w", newError(path, err))
+	}
+
+	tags := make(map[string]string, len(resp.TagSet))
+	for _, tag := range resp.TagSet {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+
+	return tags, nil
+}
+
+// SetTagsWithContext replaces tags of the object by the ones provided.
+func (c *s3Client) SetTagsWithContext(ctx context.Context, path S3Path, tags map[string]string) error {
+	tagSet := make([]*s3.Tag, 0, len(tags))
+	for key, value := range tags {
+		tagSet = append(tagSet, &s3.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+
+	_, err := c.awsS3.PutObjectTaggingWithContext(ctx, &s3.PutObjectTaggingInput{
+		Bucket:    aws.String(path.Bucket),
+		Key:       aws.String(path.Key),
+		VersionId: path.versionID(),
+		Tagging: &s3.Tagging{
+			TagSet: tagSet,
+		},
+		RequestPayer:        c.opts.requestPayer(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	})
+	if err != nil {
+		return fmt.Errorf("error setting tags: %w", newError(path, err))
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package s3client
+
+import (
+	"context"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+const (
+	// DefaultUploadConcurrency is a number of parts uploaded in parallel by default.
+	DefaultUploadConcurrency = 5
+)
+
+// UploadOptions contains parameters of an upload operation.
+type UploadOptions struct {
+	// PartSize is a size of a single part of a multipart upload. A body that fits into a single part
+	// is uploaded with a single PutObject request. DefaultMultipartChunkSize is used when 0.
+	PartSize int64
+	// Concurrency is a number of parts uploaded in parallel. DefaultUploadConcurrency is used when 0.
+	Concurrency int
+	// ContentType is a MIME type of the object.
+	ContentType string
+	// Metadata is a user metadata stored with the object.
+	Metadata map[string]string
+	// Encryption is a server-side encryption of the object. The client one is used when nil.
+	Encryption *Encryption
+	// IfAbsent creates the object only if it does not exist, ErrPreconditionFailed is returned otherwise.
+	IfAbsent bool
+}
+
+// PutObjectWithContext uploads a body to the S3 path using default upload options.
+func (c *s3Client) PutObjectWithContext(ctx context.Context, path S3Path, body io.Reader) error {
+	return c.UploadWithContext(ctx, path, body, UploadOptions{})
+}
+
+// UploadWithContext uploads a body to the S3 path. A body larger than a part size is uploaded
+// by a parallel multipart upload.
+func (c *s3Client) UploadWithContext(ctx context.Context, path S3Path, body io.Reader, opts UploadOptions) error {
+	uploader := s3manager.NewUploaderWithClient(c.awsS3, func(u *s3manager.Uploader) {
+		u.PartSize = DefaultMultipartChunkSize
+		if opts.PartSize > 0 {
+			u.PartSize = opts.PartSize
+		}
+
+		u.Concurrency = DefaultUploadConcurrency
+		if opts.Concurrency > 0 {
+			u.Concurrency = opts.Concurrency
+		}
+	})
+
+	input := &s3manager.UploadInput{
+		Bucket: aws.String(path.Bucket),
+		Key:    aws.String(path.Key),
+		Body:   body,
+
+		RequestPayer:        c.opts.requestPayer(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+		StorageClass:        c.opts.storageClassValue(),
+	}
+	if opts.ContentType != "" {
+		input.ContentType = aws.String(opts.ContentType)
+	}
+	if len(opts.Metadata) > 0 {
+		input.Metadata = aws.StringMap(opts.Metadata)
+	}
+
+	encryption := opts.Encryption
+	if encryption == nil {
+		encryption = c.opts.encryption
+	}
+	input.ServerSideEncryption, input.SSEKMSKeyId = encryption.serverSide()
+	input.SSECustomerAlgorithm, input.SSECustomerKey, input.SSECustomerKeyMD5 = encryption.customer()
+
+	uploadOpts := []func(*s3manager.Uploader){}
+	if opts.IfAbsent {
+		uploadOpts = append(uploadOpts, s3manager.WithUploaderRequestOptions(ifAbsentOption))
+	}
+
+	_, err := uploader.UploadWithContext(ctx, input, uploadOpts...)
+
+	return newError(path, err)
+}
@@ -0,0 +1,499 @@
+package s3client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// CheckpointFileSuffix is appended to a local file path to get a path of its upload checkpoint by default.
+	CheckpointFileSuffix = ".s3upload"
+)
+
+// UploadFileOptions contains parameters of a local file upload.
+type UploadFileOptions struct {
+	UploadOptions
+	// CheckpointFile is a path of a file the upload progress is saved to after every part uploaded,
+	// so a restarted upload of the same file resumes from the last completed part instead of starting over.
+	// A local file path with CheckpointFileSuffix is used when empty. The file is deleted once the upload completes.
+	CheckpointFile string
+	// MaxRetries is a max number of retries of a part failed with a throttling or server error.
+	// DefaultPartMaxRetries is used when 0, a negative value disables retries.
+	MaxRetries int
+	// RetryDelay is a delay before the first retry of a failed part, doubled on every next retry.
+	// DefaultPartRetryDelay is used when 0.
+	RetryDelay time.Duration
+}
+
+// withDefaults returns options with zero values replaced by defaults.
+func (o UploadFileOptions) withDefaults(localPath string) UploadFileOptions {
+	if o.PartSize <= 0 {
+		o.PartSize = DefaultMultipartChunkSize
+	}
+
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultUploadConcurrency
+	}
+
+	if o.CheckpointFile == "" {
+		o.CheckpointFile = localPath + CheckpointFileSuffix
+	}
+
+	if o.MaxRetries == 0 {
+		o.MaxRetries = DefaultPartMaxRetries
+	} else if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	}
+
+	if o.RetryDelay <= 0 {
+		o.RetryDelay = DefaultPartRetryDelay
+	}
+
+	return o
+}
+
+// uploadCheckpoint is a saved state of a multipart upload of a local file.
+// The file size and modification time are saved to not resume an upload of a file changed since.
+type uploadCheckpoint struct {
+	Path     S3Path         `json:"path"`
+	UploadID string         `json:"uploadId"`
+	Size     int64          `json:"size"`
+	ModTime  time.Time      `json:"modTime"`
+	PartSize int64          `json:"partSize"`
+	Parts    []uploadedPart `json:"parts"`
+}
+
+// uploadedPart is a part of a multipart upload completed.
+type uploadedPart struct {
+	PartNumber int64  `json:"partNumber"`
+	ETag       string `json:"eTag"`
+}
+
+// matches returns true if the checkpoint is saved for the same destination, file and part size.
+func (cp *uploadCheckpoint) matches(path S3Path, info os.FileInfo, partSize int64) bool {
+	return cp.UploadID != "" &&
+		cp.Path.Bucket == path.Bucket &&
+		cp.Path.Key == path.Key &&
+		cp.Size == info.Size() &&
+		cp.ModTime.Equal(info.ModTime()) &&
+		cp.PartSize == partSize
+}
+
+// save writes the checkpoint to a temporary file renamed to the checkpoint one,
+// so an interrupted write does not leave a broken checkpoint behind.
+func (cp *uploadCheckpoint) save(checkpointFile string) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+
+	tmpFile := checkpointFile + ".tmp"
+	if err = os.WriteFile(tmpFile, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile, checkpointFile)
+}
+
+// loadCheckpoint reads a checkpoint file, nil is returned if it does not exist.
+func loadCheckpoint(checkpointFile string) (*uploadCheckpoint, error) {
+	data, err := os.ReadFile(checkpointFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	cp := &uploadCheckpoint{}
+	if err = json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("broken checkpoint file %v, delete it to start the upload over: %w", checkpointFile, err)
+	}
+
+	return cp, nil
+}
+
+// partUploader is implemented by clients able to upload a local file part by part.
+type partUploader interface {
+	UploadWithContext(ctx context.Context, path S3Path, body io.Reader, opts UploadOptions) error
+	stat(ctx context.Context, path S3Path, callerPays bool, encryption *Encryption) (ObjectInfo, error)
+	uploadEncryption(opts UploadOptions) *Encryption
+	createMultipartUpload(ctx context.Context, path S3Path, opts UploadOptions) (string, error)
+	uploadPart(ctx context.Context, path S3Path, uploadID string, partNumber int64, body io.ReadSeeker, opts UploadOptions) (string, error)
+	completeMultipartUpload(ctx context.Context, path S3Path, uploadID string, parts []uploadedPart, opts UploadOptions) error
+	abortMultipartUpload(ctx context.Context, path S3Path, uploadID string) error
+}
+
+// uploadFile uploads a local file by parts read and uploaded in parallel saving the progress to a checkpoint file.
+// A failed or cancelled upload is not aborted to be resumed later, so its parts stay in the bucket until
+// the upload is resumed or aborted, e.g. by AbortStaleUploads. A checkpoint of an upload that no longer
+// exists is discarded and the file is uploaded over unless the object has already been assembled from its parts.
+func uploadFile(ctx context.Context, c partUploader, localPath string, path S3Path, opts UploadFileOptions) error {
+	opts = opts.withDefaults(localPath)
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("error opening a file to upload: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("error querying a file to upload: %w", err)
+	}
+
+	if info.Size() <= opts.PartSize {
+		// a single part file is uploaded by one request, so there is nothing to resume.
+		return c.UploadWithContext(ctx, path, file, opts.UploadOptions)
+	}
+
+	if parts := partsNumber(info.Size(), opts.PartSize); parts > maxUploadParts {
+		return fmt.Errorf("file %v of %v bytes is split into %v parts, more than %v allowed, increase the part size",
+			localPath, info.Size(), parts, maxUploadParts)
+	}
+
+	cp, err := loadCheckpoint(opts.CheckpointFile)
+	if err != nil {
+		return err
+	}
+
+	if cp != nil && !cp.matches(path, info, opts.PartSize) {
+		// the file or the destination has changed, so the previous upload is useless.
+		_ = c.abortMultipartUpload(ctx, cp.Path, cp.UploadID)
+		cp = nil
+	}
+
+	resumed := cp != nil
+	if !resumed {
+		if cp, err = newUploadCheckpoint(ctx, c, path, info, opts); err != nil {
+			return err
+		}
+	}
+
+	err = uploadCheckpointParts(ctx, c, file, path, cp, opts)
+	if err != nil && resumed && errorCode(err) == awsErrNoSuchUpload {
+		// the upload has been aborted, e.g. by AbortStaleUploads or a lifecycle rule,
+		// or completed while its checkpoint has not been deleted.
+		if isCheckpointUploaded(ctx, c, path, cp, opts.UploadOptions) {
+			err = nil
+		} else if cp, err = newUploadCheckpoint(ctx, c, path, info, opts); err == nil {
+			err = uploadCheckpointParts(ctx, c, file, path, cp, opts)
+		}
+	}
+
+	if err != nil {
+		if !errors.Is(err, ErrPreconditionFailed) {
+			return err
+		}
+
+		// the object exists, so there is no reason to resume the upload.
+		if abortErr := c.abortMultipartUpload(context.Background(), path, cp.UploadID); abortErr != nil {
+			return fmt.Errorf("%w; %v", err, abortErr)
+		}
+	}
+
+	if removeErr := os.Remove(opts.CheckpointFile); removeErr != nil && err == nil {
+		return fmt.Errorf("error deleting upload checkpoint: %w", removeErr)
+	}
+
+	return err
+}
+
+// newUploadCheckpoint initiates a multipart upload of the file and saves its checkpoint.
+func newUploadCheckpoint(
+	ctx context.Context,
+	c partUploader,
+	path S3Path,
+	info os.FileInfo,
+	opts UploadFileOptions,
+) (*uploadCheckpoint, error) {
+	uploadID, err := c.createMultipartUpload(ctx, path, opts.UploadOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	cp := &uploadCheckpoint{
+		Path:     S3Path{Bucket: path.Bucket, Key: path.Key},
+		UploadID: uploadID,
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+		PartSize: opts.PartSize,
+		Parts:    []uploadedPart{},
+	}
+	if err = cp.save(opts.CheckpointFile); err != nil {
+		return nil, fmt.Errorf("error saving upload checkpoint: %w", err)
+	}
+
+	return cp, nil
+}
+
+// uploadCheckpointParts uploads the file parts missing in the checkpoint and completes the upload.
+func uploadCheckpointParts(
+	ctx context.Context,
+	c partUploader,
+	file *os.File,
+	path S3Path,
+	cp *uploadCheckpoint,
+	opts UploadFileOptions,
+) error {
+	if err := uploadFileParts(ctx, c, file, path, cp, opts); err != nil {
+		return err
+	}
+
+	return c.completeMultipartUpload(ctx, path, cp.UploadID, cp.Parts, opts.UploadOptions)
+}
+
+// isCheckpointUploaded returns true if all the checkpoint parts have been uploaded and the object
+// assembled from them exists, i.e. its size and ETag match the ones of the checkpoint parts.
+func isCheckpointUploaded(ctx context.Context, c partUploader, path S3Path, cp *uploadCheckpoint, opts UploadOptions) bool {
+	if int64(len(cp.Parts)) != partsNumber(cp.Size, cp.PartSize) {
+		return false
+	}
+
+	parts := append([]uploadedPart{}, cp.Parts...)
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+
+	partETags := make([]string, len(parts))
+	for i, part := range parts {
+		partETags[i] = part.ETag
+	}
+
+	eTag, err := compositeETag(partETags)
+	if err != nil {
+		return false
+	}
+
+	// the object is queried with the same SSE-C key and payer the parts have been uploaded with.
+	info, err := c.stat(ctx, path, false, c.uploadEncryption(opts))
+
+	return err == nil && info.Size == cp.Size && info.ETag == eTag
+}
+
+// uploadFileParts uploads the file parts missing in the checkpoint and adds them to it.
+func uploadFileParts(
+	ctx context.Context,
+	c partUploader,
+	file *os.File,
+	path S3Path,
+	cp *uploadCheckpoint,
+	opts UploadFileOptions,
+) error {
+	completed := make(map[int64]bool, len(cp.Parts))
+	for _, part := range cp.Parts {
+		completed[part.PartNumber] = true
+	}
+
+	// the first failed part cancels the rest of parts being uploaded.
+	partsCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errCh := make(chan error, partsNumber(cp.Size, cp.PartSize))
+	defer close(errCh)
+
+	// limits the number of parts read and uploaded in parallel.
+	semaphore := make(chan struct{}, opts.Concurrency)
+
+	// guards the checkpoint updated by parts uploaded.
+	var cpMu sync.Mutex
+
+	var wg sync.WaitGroup
+	for i, offset := int64(1), int64(0); offset < cp.Size && partsCtx.Err() == nil; i, offset = i+1, offset+cp.PartSize {
+		if completed[i] {
+			continue
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-partsCtx.Done():
+			continue
+		}
+
+		wg.Add(1)
+		go func(partNumber, offset int64) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			partSize := cp.PartSize
+			if offset+partSize > cp.Size {
+				partSize = cp.Size - offset
+			}
+
+			var eTag string
+			err := withRetries(partsCtx, opts.MaxRetries, opts.RetryDelay, func() error {
+				var partErr error
+				// a part is re-read from the file on every retry.
+				eTag, partErr = c.uploadPart(partsCtx, path, cp.UploadID, partNumber,
+					io.NewSectionReader(file, offset, partSize), opts.UploadOptions)
+
+				return partErr
+			})
+			if err != nil {
+				errCh <- fmt.Errorf("failed to upload part %v: %w", partNumber, err)
+				cancel()
+
+				return
+			}
+
+			cpMu.Lock()
+			defer cpMu.Unlock()
+
+			cp.Parts = append(cp.Parts, uploadedPart{PartNumber: partNumber, ETag: eTag})
+			if err = cp.save(opts.CheckpointFile); err != nil {
+				errCh <- fmt.Errorf("error saving upload checkpoint: %w", err)
+				cancel()
+			}
+		}(i, offset)
+	}
+
+	// wait until all parts are uploaded.
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("file upload cancelled: %w", ctx.Err())
+	}
+
+	if len(errCh) > 0 {
+		// the first error is wrapped, so its kind can be checked by errors.Is.
+		partsErr := fmt.Errorf("multipart upload error(s): [%w]", <-errCh)
+		for len(errCh) > 0 {
+			partsErr = fmt.Errorf("%w [%v]", partsErr, <-errCh)
+		}
+
+		return partsErr
+	}
+
+	return nil
+}
+
+// UploadFileWithContext uploads a local file to the S3 path. A file larger than a part size is uploaded
+// by a multipart upload which parts are read and uploaded in parallel. The upload progress is saved
+// to a checkpoint file, so a failed or interrupted upload resumes from the last completed part
+// when it is restarted. Such an upload is not aborted, AbortStaleUploads cleans up ones never resumed.
+func (c *s3Client) UploadFileWithContext(ctx context.Context, localPath string, path S3Path, opts UploadFileOptions) error {
+	return uploadFile(ctx, c, localPath, path, opts)
+}
+
+// createMultipartUpload initiates a multipart upload and returns its ID.
+func (c *s3Client) createMultipartUpload(ctx context.Context, path S3Path, opts UploadOptions) (string, error) {
+	params := &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(path.Bucket),
+		Key:    aws.String(path.Key),
+
+		RequestPayer:        c.opts.requestPayer(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+		StorageClass:        c.opts.storageClassValue(),
+	}
+	if opts.ContentType != "" {
+		params.ContentType = aws.String(opts.ContentType)
+	}
+	if len(opts.Metadata) > 0 {
+		params.Metadata = aws.StringMap(opts.Metadata)
+	}
+
+	encryption := c.uploadEncryption(opts)
+	params.ServerSideEncryption, params.SSEKMSKeyId = encryption.serverSide()
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = encryption.customer()
+
+	upload, err := c.awsS3.CreateMultipartUploadWithContext(ctx, params)
+	if err != nil {
+		return "", fmt.Errorf("error creating multipart upload: %w", newError(path, err))
+	}
+
+	return aws.StringValue(upload.UploadId), nil
+}
+
+// uploadPart uploads a part of a multipart upload and returns its ETag.
+func (c *s3Client) uploadPart(
+	ctx context.Context,
+	path S3Path,
+	uploadID string,
+	partNumber int64,
+	body io.ReadSeeker,
+	opts UploadOptions,
+) (string, error) {
+	params := &s3.UploadPartInput{
+		Bucket:     aws.String(path.Bucket),
+		Key:        aws.String(path.Key),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int64(partNumber),
+		Body:       body,
+
+		RequestPayer:        c.opts.requestPayer(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = c.uploadEncryption(opts).customer()
+
+	res, err := c.awsS3.UploadPartWithContext(ctx, params)
+	if err != nil {
+		return "", newError(path, err)
+	}
+
+	return aws.StringValue(res.ETag), nil
+}
+
+// completeMultipartUpload completes a multipart upload of the parts sorted by their numbers.
+func (c *s3Client) completeMultipartUpload(
+	ctx context.Context,
+	path S3Path,
+	uploadID string,
+	parts []uploadedPart,
+	opts UploadOptions,
+) error {
+	partsArr := make(completedParts, len(parts))
+	for i, part := range parts {
+		partsArr[i] = &s3.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int64(part.PartNumber),
+		}
+	}
+	sort.Sort(partsArr)
+
+	params := &s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String(path.Bucket),
+		Key:      aws.String(path.Key),
+		UploadId: aws.String(uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{
+			Parts: partsArr,
+		},
+
+		RequestPayer:        c.opts.requestPayer(false),
+		ExpectedBucketOwner: c.opts.bucketOwner(),
+	}
+	params.SSECustomerAlgorithm, params.SSECustomerKey, params.SSECustomerKeyMD5 = c.uploadEncryption(opts).customer()
+
+	reqOpts := []request.Option{}
+	if opts.IfAbsent {
+		reqOpts = append(reqOpts, ifAbsentOption)
+	}
+
+	if _, err := c.awsS3.CompleteMultipartUploadWithContext(ctx, params, reqOpts...); err != nil {
+		return fmt.Errorf("error completing multipart upload: %w", newError(path, err))
+	}
+
+	return nil
+}
+
+// uploadEncryption returns an encryption of the upload or the client one if it is not set.
+func (c *s3Client) uploadEncryption(opts UploadOptions) *Encryption {
+	if opts.Encryption != nil {
+		return opts.Encryption
+	}
+
+	return c.opts.encryption
+}
@@ -0,0 +1,113 @@
+package s3client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	versionsTokenKey     = "key"
+	versionsTokenVersion = "versionId"
+)
+
+// ListVersionsWithContext returns an iterator over all versions and delete markers of objects
+// of the prefix.Bucket which keys start with the prefix.Key. Versions of a key go from the latest one.
+// A previous version is restored by copying it over the object.
+func (c *s3Client) ListVersionsWithContext(ctx context.Context, prefix S3Path) *ObjectIterator {
+	return newObjectIterator(ctx, func(ctx context.Context, token string) ([]ObjectInfo, string, error) {
+		params := &s3.ListObjectVersionsInput{
+			Bucket:              aws.String(prefix.Bucket),
+			ExpectedBucketOwner: c.opts.bucketOwner(),
+		}
+		if prefix.Key != "" {
+			params.Prefix = aws.String(prefix.Key)
+		}
+
+		if token != "" {
+			keyMarker, versionMarker, err := decodeVersionsToken(token)
+			if err != nil {
+				return nil, "", err
+			}
+			params.KeyMarker = aws.String(keyMarker)
+			params.VersionIdMarker = aws.String(versionMarker)
+		}
+
+		resp, err := c.awsS3.ListObjectVersionsWithContext(ctx, params)
+		if err != nil {
+			return nil, "", fmt.Errorf("error listing object versions: %w", newError(prefix, err))
+		}
+
+		page := make([]ObjectInfo, 0, len(resp.Versions)+len(resp.DeleteMarkers))
+		for _, v := range resp.Versions {
+			page = append(page, ObjectInfo{
+				Path: S3Path{
+					Bucket:    prefix.Bucket,
+					Key:       aws.StringValue(v.Key),
+					VersionID: aws.StringValue(v.VersionId),
+				},
+				Size:         aws.Int64Value(v.Size),
+				ETag:         aws.StringValue(v.ETag),
+				LastModified: aws.TimeValue(v.LastModified),
+				StorageClass: aws.StringValue(v.StorageClass),
+				IsLatest:     aws.BoolValue(v.IsLatest),
+			})
+		}
+
+		for _, m := range resp.DeleteMarkers {
+			page = append(page, ObjectInfo{
+				Path: S3Path{
+					Bucket:    prefix.Bucket,
+					Key:       aws.StringValue(m.Key),
+					VersionID: aws.StringValue(m.VersionId),
+				},
+				LastModified:   aws.TimeValue(m.LastModified),
+				IsLatest:       aws.BoolValue(m.IsLatest),
+				IsDeleteMarker: true,
+			})
+		}
+
+		sortVersions(page)
+
+		nextToken := ""
+		if aws.BoolValue(resp.IsTruncated) {
+			nextToken = encodeVersionsToken(aws.StringValue(resp.NextKeyMarker), aws.StringValue(resp.NextVersionIdMarker))
+		}
+
+		return page, nextToken, nil
+	})
+}
+
+// sortVersions merges versions and delete markers returned separately in the S3 order:
+// by keys and from the latest version of a key.
+func sortVersions(page []ObjectInfo) {
+	sort.SliceStable(page, func(i, j int) bool {
+		if page[i].Path.Key != page[j].Path.Key {
+			return page[i].Path.Key < page[j].Path.Key
+		}
+
+		return page[i].LastModified.After(page[j].LastModified)
+	})
+}
+
+// encodeVersionsToken encodes a key and a version a versions listing page is continued from into a token.
+func encodeVersionsToken(keyMarker, versionMarker string) string {
+	return url.Values{
+		versionsTokenKey:     []string{keyMarker},
+		versionsTokenVersion: []string{versionMarker},
+	}.Encode()
+}
+
+// decodeVersionsToken returns a key and a version a versions listing page is continued from.
+func decodeVersionsToken(token string) (keyMarker, versionMarker string, err error) {
+	marker, err := url.ParseQuery(token)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid versions listing token %v : %w", token, err)
+	}
+
+	return marker.Get(versionsTokenKey), marker.Get(versionsTokenVersion), nil
+}